@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"mike/pkg/auth"
+	"mike/utils"
+	"slices"
+	"strings"
+)
+
+// Issues an API key from the command line, e.g. the first admin key, which
+// can then issue the rest through /v1/admin/api-keys
+func main() {
+	owner := flag.String("owner", "", "who the key is for")
+	scopes := flag.String("scopes", auth.ScopeAdmin, "comma-separated scopes: "+strings.Join(auth.Scopes, ", "))
+	flag.Parse()
+
+	if strings.TrimSpace(*owner) == "" {
+		log.Fatal("-owner is required")
+	}
+	apiKey := auth.APIKey{Owner: strings.TrimSpace(*owner)}
+	for _, scope := range strings.Split(*scopes, ",") {
+		scope = strings.TrimSpace(scope)
+		if !slices.Contains(auth.Scopes, scope) {
+			log.Fatalf("Unknown scope %q", scope)
+		}
+		apiKey.Scopes = append(apiKey.Scopes, scope)
+	}
+
+	key, err := auth.CreateKey(utils.GetDatabase(), &apiKey)
+	if err != nil {
+		log.Fatalf("Error creating API key: %v", err)
+	}
+	log.Printf("Issued API key %d (%s) to %s with scopes %s", apiKey.ID, apiKey.Prefix, apiKey.Owner, strings.Join(apiKey.Scopes, ", "))
+	fmt.Println(key)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"mike/config"
+	"mike/pkg/ingest"
+	"mike/utils"
+	"os"
+	"os/signal"
+)
+
+func main() {
+	cfg := config.GetConfig()
+
+	configPath := flag.String("config", cfg.Ingest.CompetitionsFile, "competitions file listing what to sync")
+	only := flag.String("competition", "", "only sync the competition with this name")
+	flag.Parse()
+
+	ingestCfg, err := ingest.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Error loading competitions: %v", err)
+	}
+	competitions := ingestCfg.Competitions
+	if *only != "" {
+		competitions = nil
+		for _, c := range ingestCfg.Competitions {
+			if c.Name == *only {
+				competitions = append(competitions, c)
+			}
+		}
+		if len(competitions) == 0 {
+			log.Fatalf("No competition named %q in %s", *only, *configPath)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	db := utils.GetDatabase()
+	summaries, err := ingest.SyncCompetitions(ctx, db, ingest.DefaultProviders(cfg, db), competitions)
+	for _, summary := range summaries {
+		log.Printf("Synced %s", summary)
+	}
+	if err != nil {
+		log.Fatalf("Ingestion finished with errors: %v", err)
+	}
+	log.Println("Ingestion completed successfully")
+}
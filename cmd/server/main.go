@@ -1,16 +1,28 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"mike/config"
 	"mike/pkg/application"
+	"mike/pkg/ingest"
+	"mike/pkg/scheduler"
 	"mike/pkg/server"
+	"mike/pkg/webhooks"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 )
 
 func main() {
 	cfg := config.GetConfig()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid config: %v\n", err)
+	}
 
 	app, err := application.New(cfg)
 	if err != nil {
@@ -22,10 +34,57 @@ func main() {
 		log.Fatalf("Failed to create server: %v\n", err)
 	}
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var background sync.WaitGroup
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		if err := app.FixtureChanges.Run(ctx, app.DB); err != nil {
+			log.Printf("Fixture change stream stopped: %v\n", err)
+		}
+	}()
+
+	if cfg.Sync.Enabled {
+		competitions, err := ingest.LoadConfig(cfg.Ingest.CompetitionsFile)
+		if err != nil {
+			log.Fatalf("Failed to load competitions for fixture sync: %v\n", err)
+		}
+		sched := scheduler.New(cfg.Sync, app.DB, ingest.DefaultProviders(cfg, app.DB), competitions.Competitions)
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			sched.Run(ctx)
+		}()
+	}
+
+	if cfg.Webhooks.Enabled {
+		dispatcher := webhooks.NewDispatcher(cfg.Webhooks, app.DB)
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			dispatcher.Run(ctx)
+		}()
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Error shutting down server: %v\n", err)
+		}
+	}()
+
 	fmt.Println("Server is running on port ", cfg.ServerPort)
 
 	// Start the server.
 	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		log.Fatalf("Server encountered an error: %v\n", err)
 	}
+
+	// Let an in-flight sync finish and release its lock, and the stream's listener close
+	stop()
+	background.Wait()
 }
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
@@ -11,6 +13,10 @@ type Config struct {
 	Environment string
 	ServerPort  int
 	API         *APIConfig
+	Ingest      *IngestConfig
+	Sync        *SyncConfig
+	Webhooks    *WebhookConfig
+	RateLimit   *RateLimitConfig
 }
 
 type DatabaseConfig struct {
@@ -25,6 +31,58 @@ type DatabaseConfig struct {
 type APIConfig struct {
 	FootballAPIKey string
 	FootballAPIURL string
+	// FootballRecordDir, if set, saves every API-Football response there
+	FootballRecordDir string
+	// FootballReplayDir, if set, serves API-Football responses recorded there instead of calling the API
+	FootballReplayDir string
+}
+
+type IngestConfig struct {
+	// CompetitionsFile lists the competitions to sync, see config/competitions.json
+	CompetitionsFile string
+}
+
+// SyncConfig controls the fixture sync the server runs in the background
+type SyncConfig struct {
+	Enabled bool
+	// UpcomingIntervalMinutes is how often fixtures in the next UpcomingDays are refreshed
+	UpcomingIntervalMinutes int
+	UpcomingDays            int
+	// LiveIntervalSeconds is how often fixtures in play are refreshed
+	LiveIntervalSeconds int
+}
+
+// WebhookConfig controls how the server delivers webhooks
+type WebhookConfig struct {
+	Enabled bool
+	// PollIntervalSeconds is how often pending deliveries are looked for
+	PollIntervalSeconds int
+	// MaxAttempts is how many times a delivery is tried before it is marked dead
+	MaxAttempts int
+	// BackoffSeconds is the wait after the first failed attempt, doubling after each one
+	BackoffSeconds int
+	TimeoutSeconds int
+	// RetentionDays is how long events and their finished deliveries are kept
+	RetentionDays int
+}
+
+// RateLimitConfig controls how many requests each client can make
+type RateLimitConfig struct {
+	Enabled bool
+	// Store is "memory", limiting each server process on its own, or "postgres", shared by every replica
+	Store string
+	// RequestsPerMinute and Burst are each API key's limit for routes not in Routes
+	RequestsPerMinute int
+	Burst             int
+	// IPRequestsPerMinute and IPBurst are each IP's limit across every route,
+	// checked before the API key is
+	IPRequestsPerMinute int
+	IPBurst             int
+	// Routes overrides the limit for routes under a path prefix, as
+	// "prefix=requests_per_minute:burst" separated by commas
+	Routes string
+	// TrustProxy takes the client IP from X-Forwarded-For, for running behind a load balancer
+	TrustProxy bool
 }
 
 func GetConfig() *Config {
@@ -39,8 +97,37 @@ func GetConfig() *Config {
 		},
 		ServerPort: getEnvOrDefault("SERVER_PORT", 9000),
 		API: &APIConfig{
-			FootballAPIKey: getEnvOrDefault("FOOTBALL_API_KEY", ""),
-			FootballAPIURL: getEnvOrDefault("FOOTBALL_API_URL", "https://v3.football.api-sports.io"),
+			FootballAPIKey:    getEnvOrDefault("FOOTBALL_API_KEY", ""),
+			FootballAPIURL:    getEnvOrDefault("FOOTBALL_API_URL", "https://v3.football.api-sports.io"),
+			FootballRecordDir: getEnvOrDefault("FOOTBALL_API_RECORD_DIR", ""),
+			FootballReplayDir: getEnvOrDefault("FOOTBALL_API_REPLAY_DIR", ""),
+		},
+		Ingest: &IngestConfig{
+			CompetitionsFile: getEnvOrDefault("INGEST_COMPETITIONS_FILE", "config/competitions.json"),
+		},
+		Sync: &SyncConfig{
+			Enabled:                 getEnvOrDefault("SYNC_ENABLED", false),
+			UpcomingIntervalMinutes: getEnvOrDefault("SYNC_UPCOMING_INTERVAL_MINUTES", 360),
+			UpcomingDays:            getEnvOrDefault("SYNC_UPCOMING_DAYS", 14),
+			LiveIntervalSeconds:     getEnvOrDefault("SYNC_LIVE_INTERVAL_SECONDS", 60),
+		},
+		Webhooks: &WebhookConfig{
+			Enabled:             getEnvOrDefault("WEBHOOKS_ENABLED", true),
+			PollIntervalSeconds: getEnvOrDefault("WEBHOOKS_POLL_INTERVAL_SECONDS", 5),
+			MaxAttempts:         getEnvOrDefault("WEBHOOKS_MAX_ATTEMPTS", 8),
+			BackoffSeconds:      getEnvOrDefault("WEBHOOKS_BACKOFF_SECONDS", 30),
+			TimeoutSeconds:      getEnvOrDefault("WEBHOOKS_TIMEOUT_SECONDS", 10),
+			RetentionDays:       getEnvOrDefault("WEBHOOKS_RETENTION_DAYS", 30),
+		},
+		RateLimit: &RateLimitConfig{
+			Enabled:             getEnvOrDefault("RATE_LIMIT_ENABLED", true),
+			Store:               getEnvOrDefault("RATE_LIMIT_STORE", "memory"),
+			RequestsPerMinute:   getEnvOrDefault("RATE_LIMIT_REQUESTS_PER_MINUTE", 300),
+			Burst:               getEnvOrDefault("RATE_LIMIT_BURST", 60),
+			IPRequestsPerMinute: getEnvOrDefault("RATE_LIMIT_IP_REQUESTS_PER_MINUTE", 600),
+			IPBurst:             getEnvOrDefault("RATE_LIMIT_IP_BURST", 120),
+			Routes:              getEnvOrDefault("RATE_LIMIT_ROUTES", "/v1/fixtures/daterange=30:5,/v1/fixtures/stream=10:5"),
+			TrustProxy:          getEnvOrDefault("RATE_LIMIT_TRUST_PROXY", false),
 		},
 	}
 	switch strings.ToLower(os.Getenv("ENV")) {
@@ -54,6 +141,36 @@ func GetConfig() *Config {
 	return cfg
 }
 
+// Validate returns an error for settings the server can't run with, such as
+// intervals that would panic time.NewTicker
+func (cfg *Config) Validate() error {
+	var errs []error
+	if cfg.Sync.Enabled {
+		errs = append(errs,
+			positive("SYNC_UPCOMING_INTERVAL_MINUTES", cfg.Sync.UpcomingIntervalMinutes),
+			positive("SYNC_UPCOMING_DAYS", cfg.Sync.UpcomingDays),
+			positive("SYNC_LIVE_INTERVAL_SECONDS", cfg.Sync.LiveIntervalSeconds),
+		)
+	}
+	if cfg.Webhooks.Enabled {
+		errs = append(errs,
+			positive("WEBHOOKS_POLL_INTERVAL_SECONDS", cfg.Webhooks.PollIntervalSeconds),
+			positive("WEBHOOKS_MAX_ATTEMPTS", cfg.Webhooks.MaxAttempts),
+			positive("WEBHOOKS_BACKOFF_SECONDS", cfg.Webhooks.BackoffSeconds),
+			positive("WEBHOOKS_TIMEOUT_SECONDS", cfg.Webhooks.TimeoutSeconds),
+			positive("WEBHOOKS_RETENTION_DAYS", cfg.Webhooks.RetentionDays),
+		)
+	}
+	return errors.Join(errs...)
+}
+
+func positive(envVarName string, value int) error {
+	if value <= 0 {
+		return fmt.Errorf("%s must be positive, got %d", envVarName, value)
+	}
+	return nil
+}
+
 func getEnvOrDefault[T string | int | bool](envVarName string, defaultVal T) T {
 	val := os.Getenv(envVarName)
 	if val == "" {
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Validate(t *testing.T) {
+	cfg := GetConfig()
+	cfg.Sync.Enabled = true
+	cfg.Webhooks.Enabled = true
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Sync.LiveIntervalSeconds = 0
+	assert.EqualError(t, cfg.Validate(), "SYNC_LIVE_INTERVAL_SECONDS must be positive, got 0")
+	cfg.Sync.LiveIntervalSeconds = 60
+
+	cfg.Webhooks.PollIntervalSeconds = 0
+	assert.EqualError(t, cfg.Validate(), "WEBHOOKS_POLL_INTERVAL_SECONDS must be positive, got 0")
+
+	// Settings for what's turned off aren't checked
+	cfg.Webhooks.Enabled = false
+	assert.NoError(t, cfg.Validate())
+}
@@ -3,4 +3,6 @@ package config
 func loadTestConfig(cfg *Config) {
 	cfg.Environment = "test"
 	cfg.Database.Name = "mike_test_db"
+	cfg.Sync.Enabled = false
+	cfg.Webhooks.Enabled = false
 }
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS fixtures_sport_teams_datetime_key;
+
+ALTER TABLE fixtures
+    ADD CONSTRAINT fixtures_sport_teams_datetime_key
+    UNIQUE (sport_id, team_id_1, team_id_2, date_time);
+
+ALTER TABLE fixtures
+    DROP CONSTRAINT IF EXISTS fixtures_provider_external_id_key;
+ALTER TABLE fixtures DROP COLUMN external_id;
+ALTER TABLE fixtures DROP COLUMN provider;
@@ -0,0 +1,15 @@
+-- Identify provider fixtures by the provider's own id so re-ingestion updates them in place
+ALTER TABLE fixtures ADD COLUMN provider VARCHAR(50) NULL;
+ALTER TABLE fixtures ADD COLUMN external_id VARCHAR(100) NULL;
+
+ALTER TABLE fixtures
+    ADD CONSTRAINT fixtures_provider_external_id_key UNIQUE (provider, external_id);
+
+-- date_time is part of the old key, so a rescheduled provider fixture would become a new row.
+-- Keep the old key only for fixtures that don't come from a provider.
+ALTER TABLE fixtures
+    DROP CONSTRAINT IF EXISTS fixtures_sport_teams_datetime_key;
+
+CREATE UNIQUE INDEX fixtures_sport_teams_datetime_key
+    ON fixtures (sport_id, team_id_1, team_id_2, date_time)
+    WHERE external_id IS NULL;
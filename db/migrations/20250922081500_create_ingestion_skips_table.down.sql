@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_ingestion_skips_created_at;
+DROP INDEX IF EXISTS idx_ingestion_skips_provider_external_id;
+DROP TABLE IF EXISTS ingestion_skips;
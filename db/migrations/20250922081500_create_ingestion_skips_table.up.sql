@@ -0,0 +1,13 @@
+-- Provider records dropped during ingestion, with the reason they couldn't be imported
+create table ingestion_skips (
+    id serial primary key,
+    provider varchar(50) not null,
+    league_id int not null,
+    season varchar(20) not null,
+    external_id varchar(100) not null,
+    reason text not null,
+    created_at timestamp not null default current_timestamp
+);
+
+create index idx_ingestion_skips_provider_external_id on ingestion_skips (provider, external_id);
+create index idx_ingestion_skips_created_at on ingestion_skips (created_at);
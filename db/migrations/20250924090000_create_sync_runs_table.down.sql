@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_ingestion_skips_sync_run_id;
+ALTER TABLE ingestion_skips DROP COLUMN IF EXISTS sync_run_id;
+DROP INDEX IF EXISTS idx_sync_runs_provider_league_season;
+DROP INDEX IF EXISTS idx_sync_runs_started_at;
+DROP TABLE IF EXISTS sync_runs;
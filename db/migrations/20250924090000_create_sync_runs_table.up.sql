@@ -0,0 +1,22 @@
+-- One row per ingestion run, so a missing fixture can be traced to a run that failed, skipped it or never happened
+create table sync_runs (
+    id serial primary key,
+    provider varchar(50) not null,
+    kind varchar(20) not null,
+    league_id int not null,
+    season varchar(20) not null,
+    status varchar(20) not null default 'running',
+    started_at timestamp not null default current_timestamp,
+    finished_at timestamp,
+    inserted int not null default 0,
+    updated int not null default 0,
+    unchanged int not null default 0,
+    skipped int not null default 0,
+    error text
+);
+
+create index idx_sync_runs_started_at on sync_runs (started_at);
+create index idx_sync_runs_provider_league_season on sync_runs (provider, league_id, season);
+
+alter table ingestion_skips add column sync_run_id int references sync_runs (id) on delete set null;
+create index idx_ingestion_skips_sync_run_id on ingestion_skips (sync_run_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS fixture_scores;
+ALTER TABLE fixtures DROP COLUMN IF EXISTS away_score;
+ALTER TABLE fixtures DROP COLUMN IF EXISTS home_score;
@@ -0,0 +1,15 @@
+-- Running or final score; null until the fixture has started
+ALTER TABLE fixtures ADD COLUMN home_score INT NULL;
+ALTER TABLE fixtures ADD COLUMN away_score INT NULL;
+
+-- Score for each period of a fixture, named however the sport names them
+-- (halftime/fulltime for soccer, innings for baseball, periods for hockey)
+create table fixture_scores (
+    id serial primary key,
+    fixture_id int not null references fixtures(id) on delete cascade,
+    period varchar(50) not null,
+    sequence int not null,
+    home int not null,
+    away int not null,
+    constraint fixture_scores_fixture_period_key unique (fixture_id, period)
+);
@@ -0,0 +1,8 @@
+ALTER TABLE fixtures DROP CONSTRAINT IF EXISTS fixtures_status_check;
+ALTER TABLE fixtures ALTER COLUMN status TYPE VARCHAR(255);
+
+-- Restore provider codes where we kept them; other fixtures keep the canonical status
+UPDATE fixtures
+SET status = details->>'provider_status',
+    details = jsonb_set(details - 'provider_status', '{status}', details->'provider_status')
+WHERE details ? 'provider_status';
@@ -0,0 +1,43 @@
+-- Move fixtures from provider status codes to the canonical statuses in
+-- pkg/routes/fixtures/models/status.go, keeping the provider's code in details
+UPDATE fixtures
+SET details = jsonb_set(details, '{provider_status}', to_jsonb(status))
+WHERE provider IS NOT NULL;
+
+UPDATE fixtures
+SET status = CASE upper(status)
+    WHEN 'TBD' THEN 'scheduled'
+    WHEN 'NS' THEN 'scheduled'
+    WHEN '1H' THEN 'live'
+    WHEN '2H' THEN 'live'
+    WHEN 'ET' THEN 'live'
+    WHEN 'P' THEN 'live'
+    WHEN 'LIVE' THEN 'live'
+    WHEN 'HT' THEN 'halftime'
+    WHEN 'BT' THEN 'halftime'
+    WHEN 'FT' THEN 'finished'
+    WHEN 'AET' THEN 'finished'
+    WHEN 'PEN' THEN 'finished'
+    WHEN 'AWD' THEN 'finished'
+    WHEN 'WO' THEN 'finished'
+    WHEN 'SUSP' THEN 'suspended'
+    WHEN 'INT' THEN 'suspended'
+    WHEN 'PST' THEN 'postponed'
+    WHEN 'CANC' THEN 'cancelled'
+    WHEN 'ABD' THEN 'abandoned'
+    ELSE lower(status)
+END;
+
+-- Anything left over that isn't canonical is treated as not yet played
+UPDATE fixtures
+SET status = 'scheduled'
+WHERE status NOT IN ('scheduled', 'live', 'halftime', 'finished', 'postponed', 'cancelled', 'abandoned', 'suspended');
+
+UPDATE fixtures
+SET details = jsonb_set(details, '{status}', to_jsonb(status))
+WHERE details ? 'status';
+
+ALTER TABLE fixtures ALTER COLUMN status TYPE VARCHAR(20);
+ALTER TABLE fixtures
+    ADD CONSTRAINT fixtures_status_check
+    CHECK (status IN ('scheduled', 'live', 'halftime', 'finished', 'postponed', 'cancelled', 'abandoned', 'suspended'));
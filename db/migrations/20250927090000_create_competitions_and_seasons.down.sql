@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_fixtures_season_id_date_time;
+ALTER TABLE fixtures DROP COLUMN IF EXISTS season_id;
+DROP TABLE IF EXISTS season_teams;
+DROP TABLE IF EXISTS seasons;
+DROP TABLE IF EXISTS competitions;
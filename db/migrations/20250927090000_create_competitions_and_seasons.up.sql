@@ -0,0 +1,36 @@
+-- Leagues and cups, e.g. Premier League and FA Cup, so fixtures between the same
+-- clubs can be told apart
+create table competitions (
+    id serial primary key,
+    sport_id int not null references sports(id),
+    name varchar(255) not null,
+    -- provider and external_id identify competitions imported from a data provider
+    provider varchar(50) null,
+    external_id varchar(100) null,
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp not null default current_timestamp,
+    constraint competitions_provider_external_id_key unique (provider, external_id)
+);
+
+create index idx_competitions_sport_id on competitions (sport_id);
+
+-- One edition of a competition, named as the provider names it (e.g. "2025")
+create table seasons (
+    id serial primary key,
+    competition_id int not null references competitions(id) on delete cascade,
+    name varchar(20) not null,
+    created_at timestamp not null default current_timestamp,
+    constraint seasons_competition_name_key unique (competition_id, name)
+);
+
+-- Teams taking part in a season
+create table season_teams (
+    season_id int not null references seasons(id) on delete cascade,
+    team_id int not null references teams(id) on delete cascade,
+    primary key (season_id, team_id)
+);
+
+create index idx_season_teams_team_id on season_teams (team_id);
+
+ALTER TABLE fixtures ADD COLUMN season_id INT NULL REFERENCES seasons(id);
+CREATE INDEX idx_fixtures_season_id_date_time ON fixtures (season_id, date_time, id);
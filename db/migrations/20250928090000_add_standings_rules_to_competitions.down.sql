@@ -0,0 +1 @@
+ALTER TABLE competitions DROP COLUMN IF EXISTS standings_rules;
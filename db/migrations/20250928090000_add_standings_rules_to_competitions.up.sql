@@ -0,0 +1,2 @@
+-- Points and tiebreak rules for a competition's table; null uses the sport's defaults
+ALTER TABLE competitions ADD COLUMN standings_rules JSONB NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_fixtures_venue_id;
+ALTER TABLE fixtures DROP COLUMN IF EXISTS venue_id;
+ALTER TABLE teams DROP COLUMN IF EXISTS venue_id;
+DROP TABLE IF EXISTS venues;
@@ -0,0 +1,31 @@
+-- Stadiums and arenas. Teams have a home venue; fixtures are played at a venue.
+create table venues (
+    id serial primary key,
+    name varchar(255) not null,
+    address varchar(255) null,
+    city varchar(255) null,
+    capacity int null,
+    surface varchar(50) null,
+    image_url varchar(500) null,
+    -- provider and external_id identify venues imported from a data provider
+    provider varchar(50) null,
+    external_id varchar(100) null,
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp not null default current_timestamp,
+    constraint venues_provider_external_id_key unique (provider, external_id)
+);
+
+ALTER TABLE teams ADD COLUMN venue_id INT NULL REFERENCES venues(id) ON DELETE SET NULL;
+ALTER TABLE fixtures ADD COLUMN venue_id INT NULL REFERENCES venues(id) ON DELETE SET NULL;
+CREATE INDEX idx_fixtures_venue_id ON fixtures (venue_id);
+
+-- Seeded fixtures carry a venue name in details; give each name a venue row
+INSERT INTO venues (name)
+SELECT DISTINCT details->>'venue'
+FROM fixtures
+WHERE details->>'venue' IS NOT NULL AND details->>'venue' <> '';
+
+UPDATE fixtures f
+SET venue_id = v.id
+FROM venues v
+WHERE v.provider IS NULL AND v.name = f.details->>'venue';
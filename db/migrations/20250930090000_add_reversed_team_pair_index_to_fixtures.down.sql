@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_fixtures_team_id_2_team_id_1;
@@ -0,0 +1,3 @@
+-- idx_fixtures_team_id_1 covers (team_id_1, team_id_2); head-to-head lookups also
+-- need the reversed pairing, and team_id_2 alone for a team's away fixtures
+CREATE INDEX idx_fixtures_team_id_2_team_id_1 ON fixtures (team_id_2, team_id_1);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_teams_name_search;
+DROP FUNCTION IF EXISTS search_normalize(text);
@@ -0,0 +1,11 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent() is only STABLE because its dictionary can change, so it can't be
+-- used in an index directly. Naming the dictionary makes this safe to treat as
+-- IMMUTABLE.
+CREATE OR REPLACE FUNCTION search_normalize(value text) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+    AS $$ SELECT lower(public.unaccent('public.unaccent'::regdictionary, value)) $$;
+
+CREATE INDEX idx_teams_name_search ON teams USING gin (search_normalize(name) gin_trgm_ops);
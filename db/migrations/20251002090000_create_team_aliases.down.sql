@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS team_alias_reviews;
+DROP TABLE IF EXISTS team_aliases;
+DROP INDEX IF EXISTS idx_teams_sport_id_normalized_name;
+DROP FUNCTION IF EXISTS team_name_normalize(text);
@@ -0,0 +1,60 @@
+-- team_name_normalize reduces a team name to what is left once case, accents,
+-- punctuation and common club affixes are ignored, so "Manchester United FC"
+-- and "manchester united" compare equal.
+CREATE OR REPLACE FUNCTION team_name_normalize(value text) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+    AS $$
+    SELECT btrim(regexp_replace(regexp_replace(regexp_replace(
+        regexp_replace(search_normalize(value), '[^[:alnum:]]+', ' ', 'g'),
+        '\m(fc|afc|cf|sc|ac|club|the)\M', ' ', 'g'),
+        '\mutd\M', 'united', 'g'),
+        '\s+', ' ', 'g'))
+    $$;
+
+CREATE INDEX idx_teams_sport_id_normalized_name ON teams (sport_id, team_name_normalize(name));
+
+-- Other names a team is known by. An alias with an external_id maps a
+-- provider's team id onto our team; one without maps a name seen from that
+-- provider.
+create table team_aliases (
+    id serial primary key,
+    team_id int not null references teams(id) on delete cascade,
+    sport_id int not null references sports(id),
+    provider varchar(50) not null,
+    external_id varchar(100) null,
+    name varchar(255) not null,
+    normalized_name text generated always as (team_name_normalize(name)) stored,
+    created_at timestamp not null default current_timestamp
+);
+
+create unique index idx_team_aliases_external_id on team_aliases (sport_id, provider, external_id) where external_id is not null;
+create unique index idx_team_aliases_name on team_aliases (sport_id, provider, normalized_name) where external_id is null;
+create index idx_team_aliases_normalized_name on team_aliases (sport_id, normalized_name);
+create index idx_team_aliases_team_id on team_aliases (team_id);
+
+-- Names that only resembled a stored team, waiting for someone to approve the
+-- match or reject it so the name becomes a team of its own
+create table team_alias_reviews (
+    id serial primary key,
+    sport_id int not null references sports(id),
+    provider varchar(50) not null,
+    external_id varchar(100) null,
+    name varchar(255) not null,
+    candidate_team_id int null references teams(id) on delete set null,
+    similarity real not null default 0,
+    status varchar(20) not null default 'pending',
+    team_id int null references teams(id) on delete set null,
+    created_at timestamp not null default current_timestamp,
+    resolved_at timestamp null,
+    constraint team_alias_reviews_status_check check (status in ('pending', 'approved', 'rejected')),
+    constraint team_alias_reviews_sport_provider_name_key unique (sport_id, provider, name)
+);
+
+create index idx_team_alias_reviews_status on team_alias_reviews (status);
+
+-- Every team with an API-Football id came from API-Football
+INSERT INTO team_aliases (team_id, sport_id, provider, external_id, name)
+SELECT id, sport_id, 'api-football', api_id::text, name
+FROM teams
+WHERE api_id IS NOT NULL AND api_id <> 0
+ON CONFLICT DO NOTHING;
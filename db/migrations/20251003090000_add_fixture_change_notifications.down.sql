@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS fixtures_notify_change ON fixtures;
+DROP FUNCTION IF EXISTS notify_fixture_change();
@@ -0,0 +1,35 @@
+-- Notify listeners on fixture_changes when a fixture is added or its status,
+-- kickoff time or score changes. Kickoff times are stored in UTC.
+CREATE OR REPLACE FUNCTION notify_fixture_change() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+        AND NEW.status IS NOT DISTINCT FROM OLD.status
+        AND NEW.date_time IS NOT DISTINCT FROM OLD.date_time
+        AND NEW.home_score IS NOT DISTINCT FROM OLD.home_score
+        AND NEW.away_score IS NOT DISTINCT FROM OLD.away_score THEN
+        RETURN NEW;
+    END IF;
+
+    PERFORM pg_notify('fixture_changes', json_build_object(
+        'op', lower(TG_OP),
+        'id', NEW.id,
+        'sport_id', NEW.sport_id,
+        'season_id', NEW.season_id,
+        'team_id_1', NEW.team_id_1,
+        'team_id_2', NEW.team_id_2,
+        'status', NEW.status,
+        'date_time', to_char(NEW.date_time, 'YYYY-MM-DD"T"HH24:MI:SS"Z"'),
+        'home_score', NEW.home_score,
+        'away_score', NEW.away_score,
+        'previous_status', CASE WHEN TG_OP = 'UPDATE' THEN OLD.status END,
+        'previous_date_time', CASE WHEN TG_OP = 'UPDATE' THEN to_char(OLD.date_time, 'YYYY-MM-DD"T"HH24:MI:SS"Z"') END
+    )::text);
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER fixtures_notify_change
+    AFTER INSERT OR UPDATE ON fixtures
+    FOR EACH ROW EXECUTE FUNCTION notify_fixture_change();
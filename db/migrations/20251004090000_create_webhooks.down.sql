@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS fixtures_record_events ON fixtures;
+DROP FUNCTION IF EXISTS record_fixture_events();
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS fixture_events;
+DROP TABLE IF EXISTS webhook_subscriptions;
@@ -0,0 +1,106 @@
+-- Endpoints API consumers want fixture events delivered to
+create table webhook_subscriptions (
+    id serial primary key,
+    url varchar(2048) not null,
+    secret varchar(255) not null,
+    event_types text[] not null,
+    sport_id int null references sports(id) on delete cascade,
+    team_id int null references teams(id) on delete cascade,
+    is_active boolean not null default true,
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp not null default current_timestamp
+);
+
+-- Every fixture event, written in the same transaction as the fixture change
+create table fixture_events (
+    id bigserial primary key,
+    event_type varchar(50) not null,
+    fixture_id int not null references fixtures(id) on delete cascade,
+    payload jsonb not null,
+    created_at timestamp not null default current_timestamp
+);
+
+create index idx_fixture_events_fixture_id on fixture_events (fixture_id);
+
+-- One row per event per matching subscription. Failed deliveries are retried
+-- until max attempts, then left as dead for someone to look at and retry.
+create table webhook_deliveries (
+    id bigserial primary key,
+    subscription_id int not null references webhook_subscriptions(id) on delete cascade,
+    event_id bigint not null references fixture_events(id) on delete cascade,
+    status varchar(20) not null default 'pending',
+    attempts int not null default 0,
+    next_attempt_at timestamp not null default current_timestamp,
+    last_status_code int null,
+    last_error text null,
+    created_at timestamp not null default current_timestamp,
+    delivered_at timestamp null,
+    constraint webhook_deliveries_status_check check (status in ('pending', 'delivered', 'dead')),
+    constraint webhook_deliveries_subscription_event_key unique (subscription_id, event_id)
+);
+
+create index idx_webhook_deliveries_due on webhook_deliveries (next_attempt_at) where status = 'pending';
+create index idx_webhook_deliveries_subscription_status on webhook_deliveries (subscription_id, status);
+
+-- Record fixture.created, fixture.status_changed and fixture.rescheduled events
+-- for every write to fixtures, whichever code path made it, and queue a
+-- delivery to each matching subscription.
+CREATE OR REPLACE FUNCTION record_fixture_events() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+    fixture jsonb;
+    kinds text[] := '{}';
+    kind text;
+    new_event_id bigint;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        kinds := array_append(kinds, 'fixture.created');
+    ELSE
+        IF NEW.status IS DISTINCT FROM OLD.status THEN
+            kinds := array_append(kinds, 'fixture.status_changed');
+        END IF;
+        IF NEW.date_time IS DISTINCT FROM OLD.date_time THEN
+            kinds := array_append(kinds, 'fixture.rescheduled');
+        END IF;
+    END IF;
+    IF cardinality(kinds) = 0 THEN
+        RETURN NEW;
+    END IF;
+
+    fixture := jsonb_build_object(
+        'id', NEW.id,
+        'sport_id', NEW.sport_id,
+        'season_id', NEW.season_id,
+        'team_id_1', NEW.team_id_1,
+        'team_id_2', NEW.team_id_2,
+        'status', NEW.status,
+        'date_time', to_char(NEW.date_time, 'YYYY-MM-DD"T"HH24:MI:SS"Z"'),
+        'home_score', NEW.home_score,
+        'away_score', NEW.away_score
+    );
+
+    FOREACH kind IN ARRAY kinds LOOP
+        INSERT INTO fixture_events (event_type, fixture_id, payload)
+        VALUES (kind, NEW.id, jsonb_build_object(
+            'fixture', fixture,
+            'previous_status', CASE WHEN kind = 'fixture.status_changed' THEN OLD.status END,
+            'previous_date_time', CASE WHEN kind = 'fixture.rescheduled' THEN to_char(OLD.date_time, 'YYYY-MM-DD"T"HH24:MI:SS"Z"') END
+        ))
+        RETURNING id INTO new_event_id;
+
+        INSERT INTO webhook_deliveries (subscription_id, event_id)
+        SELECT s.id, new_event_id
+        FROM webhook_subscriptions s
+        WHERE s.is_active
+            AND kind = ANY (s.event_types)
+            AND (s.sport_id IS NULL OR s.sport_id = NEW.sport_id)
+            AND (s.team_id IS NULL OR s.team_id IN (NEW.team_id_1, NEW.team_id_2));
+    END LOOP;
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER fixtures_record_events
+    AFTER INSERT OR UPDATE ON fixtures
+    FOR EACH ROW EXECUTE FUNCTION record_fixture_events();
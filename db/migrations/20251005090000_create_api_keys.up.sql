@@ -0,0 +1,15 @@
+-- Keys API consumers authenticate with. Only a SHA-256 hash of each key is
+-- kept; the key itself is shown once, when it's issued.
+create table api_keys (
+    id serial primary key,
+    owner varchar(255) not null,
+    -- prefix is the start of the key, to tell keys apart without storing them
+    prefix varchar(16) not null,
+    key_hash char(64) not null,
+    scopes text[] not null,
+    created_at timestamp not null default current_timestamp,
+    expires_at timestamp null,
+    last_used_at timestamp null,
+    revoked_at timestamp null,
+    constraint api_keys_key_hash_key unique (key_hash)
+);
@@ -0,0 +1,13 @@
+-- Token buckets for RATE_LIMIT_STORE=postgres, shared by every replica. They're
+-- only worth keeping for a few minutes, so the table is unlogged.
+create unlogged table rate_limit_buckets (
+    key varchar(255) primary key,
+    tokens double precision not null,
+    -- allowed is whether the last request took a token
+    allowed boolean not null,
+    updated_at timestamptz not null,
+    -- refill_seconds is how long the bucket takes to fill up, after which it can be deleted
+    refill_seconds double precision not null
+);
+
+create index idx_rate_limit_buckets_updated_at on rate_limit_buckets (updated_at);
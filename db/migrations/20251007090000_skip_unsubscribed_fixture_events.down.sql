@@ -0,0 +1,57 @@
+DROP INDEX IF EXISTS idx_fixture_events_created_at;
+
+CREATE OR REPLACE FUNCTION record_fixture_events() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+    fixture jsonb;
+    kinds text[] := '{}';
+    kind text;
+    new_event_id bigint;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        kinds := array_append(kinds, 'fixture.created');
+    ELSE
+        IF NEW.status IS DISTINCT FROM OLD.status THEN
+            kinds := array_append(kinds, 'fixture.status_changed');
+        END IF;
+        IF NEW.date_time IS DISTINCT FROM OLD.date_time THEN
+            kinds := array_append(kinds, 'fixture.rescheduled');
+        END IF;
+    END IF;
+    IF cardinality(kinds) = 0 THEN
+        RETURN NEW;
+    END IF;
+
+    fixture := jsonb_build_object(
+        'id', NEW.id,
+        'sport_id', NEW.sport_id,
+        'season_id', NEW.season_id,
+        'team_id_1', NEW.team_id_1,
+        'team_id_2', NEW.team_id_2,
+        'status', NEW.status,
+        'date_time', to_char(NEW.date_time, 'YYYY-MM-DD"T"HH24:MI:SS"Z"'),
+        'home_score', NEW.home_score,
+        'away_score', NEW.away_score
+    );
+
+    FOREACH kind IN ARRAY kinds LOOP
+        INSERT INTO fixture_events (event_type, fixture_id, payload)
+        VALUES (kind, NEW.id, jsonb_build_object(
+            'fixture', fixture,
+            'previous_status', CASE WHEN kind = 'fixture.status_changed' THEN OLD.status END,
+            'previous_date_time', CASE WHEN kind = 'fixture.rescheduled' THEN to_char(OLD.date_time, 'YYYY-MM-DD"T"HH24:MI:SS"Z"') END
+        ))
+        RETURNING id INTO new_event_id;
+
+        INSERT INTO webhook_deliveries (subscription_id, event_id)
+        SELECT s.id, new_event_id
+        FROM webhook_subscriptions s
+        WHERE s.is_active
+            AND kind = ANY (s.event_types)
+            AND (s.sport_id IS NULL OR s.sport_id = NEW.sport_id)
+            AND (s.team_id IS NULL OR s.team_id IN (NEW.team_id_1, NEW.team_id_2));
+    END LOOP;
+    RETURN NEW;
+END;
+$$;
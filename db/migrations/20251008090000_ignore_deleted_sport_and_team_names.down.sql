@@ -0,0 +1,8 @@
+-- Fails if a deleted sport or team shares its name with one in use
+DROP INDEX IF EXISTS teams_name_sport_id_key;
+ALTER TABLE teams
+    ADD CONSTRAINT teams_name_sport_id_key UNIQUE (name, sport_id);
+
+DROP INDEX IF EXISTS sports_name_key;
+ALTER TABLE sports
+    ADD CONSTRAINT sports_name_key UNIQUE (name);
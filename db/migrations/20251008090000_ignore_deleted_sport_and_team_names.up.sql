@@ -0,0 +1,7 @@
+-- Deleted sports and teams keep their rows, so only names still in use need to
+-- be unique; otherwise a deleted one blocks creating it again
+ALTER TABLE sports DROP CONSTRAINT IF EXISTS sports_name_key;
+CREATE UNIQUE INDEX sports_name_key ON sports (name) WHERE date_deleted IS NULL;
+
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_name_sport_id_key;
+CREATE UNIQUE INDEX teams_name_sport_id_key ON teams (name, sport_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE api_keys DROP CONSTRAINT IF EXISTS api_keys_feed_token_hash_key;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS feed_token_hash;
@@ -0,0 +1,4 @@
+-- Feed tokens stand in for a key in the query string of the calendar and
+-- stream feeds, whose clients can't send headers. Only their hash is kept.
+ALTER TABLE api_keys ADD COLUMN feed_token_hash char(64) NULL;
+ALTER TABLE api_keys ADD CONSTRAINT api_keys_feed_token_hash_key UNIQUE (feed_token_hash);
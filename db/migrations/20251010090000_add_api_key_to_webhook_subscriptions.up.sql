@@ -0,0 +1,6 @@
+-- Subscriptions belong to the API key that registered them, and are only
+-- listed, shown and deleted for that key. Ones registered before then have no
+-- key; they're still delivered but can only be managed from psql.
+ALTER TABLE webhook_subscriptions
+    ADD COLUMN api_key_id INT NULL REFERENCES api_keys(id) ON DELETE CASCADE;
+CREATE INDEX idx_webhook_subscriptions_api_key_id ON webhook_subscriptions (api_key_id, id);
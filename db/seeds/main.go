@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"mike/pkg/routes/sports/models"
+	venueModels "mike/pkg/routes/venues/models"
 	"mike/utils"
 	"time"
 
@@ -109,41 +110,51 @@ func SeedFixtures(db *bun.DB) {
 		TeamID1  int
 		TeamID2  int
 		DateTime time.Time
+		Venue    string
 		Details  map[string]interface{}
 		Status   string
 	}{
 		// Baseball fixtures (Teams 1-5)
-		{1, 1, 2, time.Date(2024, 3, 15, 19, 0, 0, 0, time.UTC), map[string]interface{}{"venue": "Yankee Stadium", "season": "2024"}, "scheduled"},
-		{1, 3, 4, time.Date(2024, 3, 16, 20, 0, 0, 0, time.UTC), map[string]interface{}{"venue": "Dodger Stadium", "season": "2024"}, "scheduled"},
-		{1, 5, 1, time.Date(2024, 3, 17, 19, 30, 0, 0, time.UTC), map[string]interface{}{"venue": "Oracle Park", "season": "2024"}, "scheduled"},
+		{1, 1, 2, time.Date(2024, 3, 15, 19, 0, 0, 0, time.UTC), "Yankee Stadium", map[string]interface{}{"season": "2024"}, "scheduled"},
+		{1, 3, 4, time.Date(2024, 3, 16, 20, 0, 0, 0, time.UTC), "Dodger Stadium", map[string]interface{}{"season": "2024"}, "scheduled"},
+		{1, 5, 1, time.Date(2024, 3, 17, 19, 30, 0, 0, time.UTC), "Oracle Park", map[string]interface{}{"season": "2024"}, "scheduled"},
 
 		// Basketball fixtures (Teams 6-10)
-		{2, 6, 7, time.Date(2024, 3, 20, 20, 0, 0, 0, time.UTC), map[string]interface{}{"venue": "Crypto.com Arena", "season": "2024"}, "scheduled"},
-		{2, 8, 9, time.Date(2024, 3, 21, 19, 30, 0, 0, time.UTC), map[string]interface{}{"venue": "Chase Center", "season": "2024"}, "scheduled"},
-		{2, 10, 6, time.Date(2024, 3, 22, 20, 0, 0, 0, time.UTC), map[string]interface{}{"venue": "FTX Arena", "season": "2024"}, "scheduled"},
+		{2, 6, 7, time.Date(2024, 3, 20, 20, 0, 0, 0, time.UTC), "Crypto.com Arena", map[string]interface{}{"season": "2024"}, "scheduled"},
+		{2, 8, 9, time.Date(2024, 3, 21, 19, 30, 0, 0, time.UTC), "Chase Center", map[string]interface{}{"season": "2024"}, "scheduled"},
+		{2, 10, 6, time.Date(2024, 3, 22, 20, 0, 0, 0, time.UTC), "FTX Arena", map[string]interface{}{"season": "2024"}, "scheduled"},
 
 		// Football fixtures (Teams 11-15)
-		{3, 11, 12, time.Date(2024, 3, 25, 13, 0, 0, 0, time.UTC), map[string]interface{}{"venue": "Gillette Stadium", "season": "2024"}, "scheduled"},
-		{3, 13, 14, time.Date(2024, 3, 26, 16, 0, 0, 0, time.UTC), map[string]interface{}{"venue": "Lambeau Field", "season": "2024"}, "scheduled"},
-		{3, 15, 11, time.Date(2024, 3, 27, 20, 0, 0, 0, time.UTC), map[string]interface{}{"venue": "AT&T Stadium", "season": "2024"}, "scheduled"},
+		{3, 11, 12, time.Date(2024, 3, 25, 13, 0, 0, 0, time.UTC), "Gillette Stadium", map[string]interface{}{"season": "2024"}, "scheduled"},
+		{3, 13, 14, time.Date(2024, 3, 26, 16, 0, 0, 0, time.UTC), "Lambeau Field", map[string]interface{}{"season": "2024"}, "scheduled"},
+		{3, 15, 11, time.Date(2024, 3, 27, 20, 0, 0, 0, time.UTC), "AT&T Stadium", map[string]interface{}{"season": "2024"}, "scheduled"},
 
 		// Hockey fixtures (Teams 16-20)
-		{4, 16, 17, time.Date(2024, 3, 30, 19, 0, 0, 0, time.UTC), map[string]interface{}{"venue": "TD Garden", "season": "2024"}, "scheduled"},
-		{4, 18, 19, time.Date(2024, 3, 31, 19, 30, 0, 0, time.UTC), map[string]interface{}{"venue": "Bell Centre", "season": "2024"}, "scheduled"},
-		{4, 20, 16, time.Date(2024, 4, 1, 19, 0, 0, 0, time.UTC), map[string]interface{}{"venue": "Madison Square Garden", "season": "2024"}, "scheduled"},
+		{4, 16, 17, time.Date(2024, 3, 30, 19, 0, 0, 0, time.UTC), "TD Garden", map[string]interface{}{"season": "2024"}, "scheduled"},
+		{4, 18, 19, time.Date(2024, 3, 31, 19, 30, 0, 0, time.UTC), "Bell Centre", map[string]interface{}{"season": "2024"}, "scheduled"},
+		{4, 20, 16, time.Date(2024, 4, 1, 19, 0, 0, 0, time.UTC), "Madison Square Garden", map[string]interface{}{"season": "2024"}, "scheduled"},
 
 		// Soccer fixtures (Teams 21-25)
-		{5, 21, 22, time.Date(2024, 4, 5, 15, 0, 0, 0, time.UTC), map[string]interface{}{"venue": "Old Trafford", "season": "2024"}, "scheduled"},
-		{5, 23, 24, time.Date(2024, 4, 6, 16, 0, 0, 0, time.UTC), map[string]interface{}{"venue": "Camp Nou", "season": "2024"}, "scheduled"},
-		{5, 25, 21, time.Date(2024, 4, 7, 14, 30, 0, 0, time.UTC), map[string]interface{}{"venue": "Allianz Arena", "season": "2024"}, "scheduled"},
+		{5, 21, 22, time.Date(2024, 4, 5, 15, 0, 0, 0, time.UTC), "Old Trafford", map[string]interface{}{"season": "2024"}, "scheduled"},
+		{5, 23, 24, time.Date(2024, 4, 6, 16, 0, 0, 0, time.UTC), "Camp Nou", map[string]interface{}{"season": "2024"}, "scheduled"},
+		{5, 25, 21, time.Date(2024, 4, 7, 14, 30, 0, 0, time.UTC), "Allianz Arena", map[string]interface{}{"season": "2024"}, "scheduled"},
 	}
 
+	venueIDs := make(map[string]int)
 	for _, fixture := range fixtures {
+		if _, ok := venueIDs[fixture.Venue]; !ok {
+			venue := venueModels.Venue{Name: fixture.Venue}
+			if _, err := db.NewInsert().Model(&venue).Returning("id").Exec(context.Background()); err != nil {
+				log.Printf("Error inserting venue %s: %v", fixture.Venue, err)
+			}
+			venueIDs[fixture.Venue] = venue.ID
+		}
 		_, err := db.NewInsert().Model(&Fixture{
 			SportID:  fixture.SportID,
 			TeamID1:  fixture.TeamID1,
 			TeamID2:  fixture.TeamID2,
 			DateTime: fixture.DateTime,
+			VenueID:  venueIDs[fixture.Venue],
 			Details:  fixture.Details,
 			Status:   fixture.Status,
 		}).Exec(context.Background())
@@ -171,6 +182,7 @@ type Fixture struct {
 	TeamID1  int                    `bun:"team_id_1" json:"team_id_1"`
 	TeamID2  int                    `bun:"team_id_2" json:"team_id_2"`
 	DateTime time.Time              `bun:"date_time" json:"date_time"`
+	VenueID  int                    `bun:"venue_id,nullzero" json:"venue_id"`
 	Details  map[string]interface{} `bun:"details" json:"details"`
 	Status   string                 `bun:"status" json:"status"`
 }
@@ -2,6 +2,7 @@ package application
 
 import (
 	"mike/config"
+	"mike/pkg/fixturestream"
 	"mike/utils"
 
 	"github.com/uptrace/bun"
@@ -10,14 +11,17 @@ import (
 type App struct {
 	Config *config.Config
 	DB     *bun.DB
+	// FixtureChanges streams fixture changes to clients once its Run is started
+	FixtureChanges *fixturestream.Hub
 }
 
 func New(cfg *config.Config) (*App, error) {
 	// Initialize database connection
 	db := utils.GetDatabase()
 	app := &App{
-		Config: cfg,
-		DB:     db,
+		Config:         cfg,
+		DB:             db,
+		FixtureChanges: fixturestream.NewHub(),
 	}
 	return app, nil
 }
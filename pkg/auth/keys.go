@@ -0,0 +1,199 @@
+// Package auth authenticates API requests with API keys and checks their scopes
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"mike/pkg/pagination"
+	"slices"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Scopes a key can be issued with
+const (
+	ScopeReadSports       = "read:sports"
+	ScopeReadTeams        = "read:teams"
+	ScopeReadFixtures     = "read:fixtures"
+	ScopeReadCompetitions = "read:competitions"
+	ScopeReadVenues       = "read:venues"
+	// ScopeWebhooks lets a key register webhook subscriptions and manage its own
+	ScopeWebhooks = "webhooks"
+	// ScopeAdmin grants every other scope too
+	ScopeAdmin = "admin"
+)
+
+// Scopes are all the scopes
+var Scopes = []string{ScopeReadSports, ScopeReadTeams, ScopeReadFixtures, ScopeReadCompetitions, ScopeReadVenues, ScopeWebhooks, ScopeAdmin}
+
+const (
+	// keyPrefix starts every key, so a leaked one is easy to recognise
+	keyPrefix = "mk_"
+	// feedTokenPrefix starts every feed token
+	feedTokenPrefix = "mf_"
+	// displayPrefixLength is how much of a key is kept to tell it apart
+	displayPrefixLength = len(keyPrefix) + 8
+	// touchInterval is how stale last_used_at can get before it's updated
+	touchInterval = time.Minute
+)
+
+// APIKey is a key issued to an owner, such as a partner team
+type APIKey struct {
+	bun.BaseModel `bun:"api_keys,alias:api_key"`
+	ID            int    `bun:"id,pk,autoincrement" json:"id"`
+	Owner         string `bun:"owner" json:"owner"`
+	// Prefix is the start of the key, shown to tell keys apart
+	Prefix  string `bun:"prefix" json:"prefix"`
+	KeyHash string `bun:"key_hash" json:"-"`
+	// FeedTokenHash is the hash of the key's feed token, if it has one
+	FeedTokenHash string     `bun:"feed_token_hash,nullzero" json:"-"`
+	Scopes        []string   `bun:"scopes,array" json:"scopes"`
+	CreatedAt     time.Time  `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
+	ExpiresAt     *time.Time `bun:"expires_at" json:"expires_at,omitempty"`
+	LastUsedAt    *time.Time `bun:"last_used_at" json:"last_used_at,omitempty"`
+	RevokedAt     *time.Time `bun:"revoked_at" json:"revoked_at,omitempty"`
+}
+
+// HasScope reports whether the key grants scope, which admin keys always do
+func (k APIKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope) || slices.Contains(k.Scopes, ScopeAdmin)
+}
+
+// Active reports whether the key can still be used at now
+func (k APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// HashKey returns the hex SHA-256 of a key, as stored in key_hash. Keys are
+// random enough that a slow password hash would add nothing.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateToken returns a new random key or feed token starting with prefix
+func generateToken(prefix string) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return prefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CreateKey issues a new key for apiKey's owner and scopes, filling in the rest
+// of apiKey, and returns the key. It can't be recovered afterwards.
+func CreateKey(db *bun.DB, apiKey *APIKey) (string, error) {
+	key, err := generateToken(keyPrefix)
+	if err != nil {
+		return "", err
+	}
+	apiKey.Prefix = key[:displayPrefixLength]
+	apiKey.KeyHash = HashKey(key)
+	if _, err := db.NewInsert().Model(apiKey).Returning("*").Exec(context.Background()); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// FindKey returns the key with key's hash, whether or not it's still active
+func FindKey(ctx context.Context, db bun.IDB, key string) (APIKey, error) {
+	var apiKey APIKey
+	err := db.NewSelect().Model(&apiKey).Where("key_hash = ?", HashKey(key)).Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return APIKey{}, errors.New("api key not found")
+		}
+		return APIKey{}, err
+	}
+	return apiKey, nil
+}
+
+// CreateFeedToken issues a feed token for the key with id, replacing any it had,
+// and returns it. It can't be recovered afterwards.
+func CreateFeedToken(db *bun.DB, id int) (string, error) {
+	token, err := generateToken(feedTokenPrefix)
+	if err != nil {
+		return "", err
+	}
+	res, err := db.NewUpdate().
+		Model((*APIKey)(nil)).
+		Set("feed_token_hash = ?", HashKey(token)).
+		Where("id = ?", id).
+		Exec(context.Background())
+	if err != nil {
+		return "", err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return "", errors.New("api key not found")
+	}
+	return token, nil
+}
+
+// FindKeyByFeedToken returns the key a feed token was issued for, whether or
+// not it's still active
+func FindKeyByFeedToken(ctx context.Context, db bun.IDB, token string) (APIKey, error) {
+	var apiKey APIKey
+	err := db.NewSelect().Model(&apiKey).Where("feed_token_hash = ?", HashKey(token)).Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return APIKey{}, errors.New("api key not found")
+		}
+		return APIKey{}, err
+	}
+	return apiKey, nil
+}
+
+func keyCursor(apiKey APIKey) pagination.Cursor {
+	return pagination.Cursor{ID: apiKey.ID}
+}
+
+// ListKeys lists keys oldest first, revoked ones included
+func ListKeys(db *bun.DB, page pagination.Params) (pagination.Page[APIKey], error) {
+	var keys []APIKey
+	q := db.NewSelect().Model(&keys)
+	if page.Cursor != nil {
+		q = q.Where("id > ?", page.Cursor.ID)
+	}
+	err := q.OrderExpr("id ASC").Limit(page.Limit + 1).Scan(context.Background())
+	if err != nil {
+		return pagination.Page[APIKey]{}, err
+	}
+	return pagination.NewPage(keys, page.Limit, keyCursor), nil
+}
+
+// RevokeKey stops a key from authenticating; it's kept for the record
+func RevokeKey(db *bun.DB, id int) (APIKey, error) {
+	var apiKey APIKey
+	err := db.NewUpdate().
+		Model(&apiKey).
+		Set("revoked_at = current_timestamp").
+		Where("id = ? AND revoked_at IS NULL", id).
+		Returning("*").
+		Scan(context.Background())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return APIKey{}, errors.New("api key not found")
+		}
+		return APIKey{}, err
+	}
+	return apiKey, nil
+}
+
+// touchKey records that a key was used at now, at most once every touchInterval
+func touchKey(ctx context.Context, db bun.IDB, apiKey APIKey, now time.Time) error {
+	if apiKey.LastUsedAt != nil && now.Sub(*apiKey.LastUsedAt) < touchInterval {
+		return nil
+	}
+	_, err := db.NewUpdate().
+		Model((*APIKey)(nil)).
+		Set("last_used_at = ?", now).
+		Where("id = ?", apiKey.ID).
+		Exec(ctx)
+	return err
+}
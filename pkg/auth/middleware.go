@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/uptrace/bun"
+)
+
+// contextKey is where Authenticate leaves the request's key
+const contextKey = "api_key"
+
+// Key returns the key the request was authenticated with
+func Key(c echo.Context) (APIKey, bool) {
+	apiKey, ok := c.Get(contextKey).(APIKey)
+	return apiKey, ok
+}
+
+// SetKey marks the request as authenticated with apiKey
+func SetKey(c echo.Context, apiKey APIKey) {
+	c.Set(contextKey, apiKey)
+}
+
+// KeyFromRequest returns the key in the Authorization: Bearer or X-API-Key
+// header. Keys in the query string aren't accepted, as URLs end up in logs;
+// feeds take a feed token there instead, see AuthenticateFeeds.
+func KeyFromRequest(r *http.Request) string {
+	if scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return r.Header.Get("X-API-Key")
+}
+
+func unauthorized(c echo.Context, message string) error {
+	c.Response().Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+	return c.JSON(http.StatusUnauthorized, map[string]string{"error": message})
+}
+
+// Authenticate rejects requests without an active API key, except to the
+// public routes, and leaves the key on the context for RequireScope. Requests
+// AuthenticateFeeds has already let in are passed on.
+func Authenticate(db *bun.DB, public ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if _, ok := Key(c); ok || slices.Contains(public, c.Path()) {
+				return next(c)
+			}
+			key := KeyFromRequest(c.Request())
+			if key == "" {
+				return unauthorized(c, "Missing API key")
+			}
+			apiKey, err := FindKey(c.Request().Context(), db, key)
+			return useKey(c, db, next, apiKey, err)
+		}
+	}
+}
+
+// AuthenticateFeeds lets requests to the feed routes authenticate with a feed
+// token in the token query parameter, for calendar apps and browser
+// EventSource clients that can't send headers. The request gets the token's
+// key with read:fixtures as its only scope, so a token leaked with its URL
+// can't be used for anything else. Requests without a token are left to
+// Authenticate.
+func AuthenticateFeeds(db *bun.DB, feeds ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token := c.QueryParam("token")
+			if token == "" || !slices.Contains(feeds, c.Path()) {
+				return next(c)
+			}
+			apiKey, err := FindKeyByFeedToken(c.Request().Context(), db, token)
+			if apiKey.HasScope(ScopeReadFixtures) {
+				apiKey.Scopes = []string{ScopeReadFixtures}
+			} else {
+				apiKey.Scopes = nil
+			}
+			return useKey(c, db, next, apiKey, err)
+		}
+	}
+}
+
+// useKey checks that the key found for a request is active and leaves it on
+// the context, answering 401 when it isn't
+func useKey(c echo.Context, db *bun.DB, next echo.HandlerFunc, apiKey APIKey, err error) error {
+	if err != nil {
+		if err.Error() == "api key not found" {
+			return unauthorized(c, "Invalid API key")
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to check API key"})
+	}
+	now := time.Now().UTC()
+	if !apiKey.Active(now) {
+		return unauthorized(c, "API key revoked or expired")
+	}
+	if err := touchKey(c.Request().Context(), db, apiKey, now); err != nil {
+		log.Printf("Recording use of API key %d: %v", apiKey.ID, err)
+	}
+
+	SetKey(c, apiKey)
+	return next(c)
+}
+
+// RequireScope rejects requests whose key doesn't grant scope
+func RequireScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			apiKey, ok := Key(c)
+			if !ok {
+				return unauthorized(c, "Missing API key")
+			}
+			if !apiKey.HasScope(scope) {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "API key lacks the " + scope + " scope"})
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"context"
+	"mike/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func ok(c echo.Context) error {
+	return c.String(http.StatusOK, "ok")
+}
+
+func Test_RequireScope(t *testing.T) {
+	tests := []struct {
+		name       string
+		key        *APIKey
+		wantStatus int
+	}{
+		{name: "no key", wantStatus: http.StatusUnauthorized},
+		{name: "key with the scope", key: &APIKey{Scopes: []string{ScopeReadTeams, ScopeReadFixtures}}, wantStatus: http.StatusOK},
+		{name: "key without the scope", key: &APIKey{Scopes: []string{ScopeReadTeams}}, wantStatus: http.StatusForbidden},
+		{name: "admin key", key: &APIKey{Scopes: []string{ScopeAdmin}}, wantStatus: http.StatusOK},
+	}
+
+	e := echo.New()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/v1/fixtures", nil), rec)
+			if test.key != nil {
+				SetKey(c, *test.key)
+			}
+			assert.NoError(t, RequireScope(ScopeReadFixtures)(ok)(c))
+			assert.Equal(t, test.wantStatus, rec.Code)
+		})
+	}
+}
+
+func Test_KeyFromRequest(t *testing.T) {
+	// Keys in the query string are ignored
+	req := httptest.NewRequest(http.MethodGet, "/v1/fixtures?api_key=from-query", nil)
+	assert.Equal(t, "", KeyFromRequest(req))
+	req.Header.Set("X-API-Key", "from-header")
+	assert.Equal(t, "from-header", KeyFromRequest(req))
+	req.Header.Set("Authorization", "bearer from-bearer")
+	assert.Equal(t, "from-bearer", KeyFromRequest(req))
+	req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+	assert.Equal(t, "from-header", KeyFromRequest(req))
+}
+
+func Test_Authenticate(t *testing.T) {
+	db := utils.GetDatabase()
+	truncate := func() {
+		_, _ = db.NewRaw("TRUNCATE TABLE api_keys").Exec(context.Background())
+	}
+	truncate()
+	defer truncate()
+
+	readKey := APIKey{Owner: "Partner", Scopes: []string{ScopeReadFixtures}}
+	read, err := CreateKey(db, &readKey)
+	assert.NoError(t, err)
+	assert.Equal(t, read[:displayPrefixLength], readKey.Prefix)
+	assert.Equal(t, HashKey(read), readKey.KeyHash)
+
+	revokedKey := APIKey{Owner: "Former partner", Scopes: []string{ScopeReadFixtures}}
+	revoked, err := CreateKey(db, &revokedKey)
+	assert.NoError(t, err)
+	_, err = RevokeKey(db, revokedKey.ID)
+	assert.NoError(t, err)
+	_, err = RevokeKey(db, revokedKey.ID)
+	assert.EqualError(t, err, "api key not found")
+
+	expiresAt := time.Now().UTC().Add(-time.Hour)
+	expiredKey := APIKey{Owner: "Trial", Scopes: []string{ScopeReadFixtures}, ExpiresAt: &expiresAt}
+	expired, err := CreateKey(db, &expiredKey)
+	assert.NoError(t, err)
+
+	e := echo.New()
+	e.Use(Authenticate(db, "/health"))
+	e.GET("/health", ok)
+	e.GET("/v1/fixtures", ok, RequireScope(ScopeReadFixtures))
+	e.GET("/v1/admin/sync-runs", ok, RequireScope(ScopeAdmin))
+
+	tests := []struct {
+		name       string
+		path       string
+		key        string
+		wantStatus int
+	}{
+		{name: "public route without a key", path: "/health", wantStatus: http.StatusOK},
+		{name: "missing key", path: "/v1/fixtures", wantStatus: http.StatusUnauthorized},
+		{name: "unknown key", path: "/v1/fixtures", key: "mk_unknown", wantStatus: http.StatusUnauthorized},
+		{name: "revoked key", path: "/v1/fixtures", key: revoked, wantStatus: http.StatusUnauthorized},
+		{name: "expired key", path: "/v1/fixtures", key: expired, wantStatus: http.StatusUnauthorized},
+		{name: "key with the scope", path: "/v1/fixtures", key: read, wantStatus: http.StatusOK},
+		{name: "key without the scope", path: "/v1/admin/sync-runs", key: read, wantStatus: http.StatusForbidden},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			if test.key != "" {
+				req.Header.Set("Authorization", "Bearer "+test.key)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, test.wantStatus, rec.Code)
+		})
+	}
+
+	used, err := FindKey(context.Background(), db, read)
+	assert.NoError(t, err)
+	assert.NotNil(t, used.LastUsedAt)
+}
+
+func Test_AuthenticateFeeds(t *testing.T) {
+	db := utils.GetDatabase()
+	truncate := func() {
+		_, _ = db.NewRaw("TRUNCATE TABLE api_keys").Exec(context.Background())
+	}
+	truncate()
+	defer truncate()
+
+	apiKey := APIKey{Owner: "Partner", Scopes: []string{ScopeReadFixtures, ScopeReadTeams}}
+	key, err := CreateKey(db, &apiKey)
+	assert.NoError(t, err)
+	replaced, err := CreateFeedToken(db, apiKey.ID)
+	assert.NoError(t, err)
+	token, err := CreateFeedToken(db, apiKey.ID)
+	assert.NoError(t, err)
+	assert.NotEqual(t, replaced, token)
+	_, err = CreateFeedToken(db, apiKey.ID+1000)
+	assert.EqualError(t, err, "api key not found")
+
+	revokedKey := APIKey{Owner: "Former partner", Scopes: []string{ScopeReadFixtures}}
+	_, err = CreateKey(db, &revokedKey)
+	assert.NoError(t, err)
+	revoked, err := CreateFeedToken(db, revokedKey.ID)
+	assert.NoError(t, err)
+	_, err = RevokeKey(db, revokedKey.ID)
+	assert.NoError(t, err)
+
+	e := echo.New()
+	e.Use(AuthenticateFeeds(db, "/v1/team/:id/fixtures.ics", "/v1/team/:id/roster.ics"))
+	e.Use(Authenticate(db, "/health"))
+	e.GET("/v1/team/:id/fixtures.ics", ok, RequireScope(ScopeReadFixtures))
+	e.GET("/v1/team/:id/roster.ics", ok, RequireScope(ScopeReadTeams))
+	e.GET("/v1/fixtures", ok, RequireScope(ScopeReadFixtures))
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{name: "feed with its token", path: "/v1/team/1/fixtures.ics?token=" + token, wantStatus: http.StatusOK},
+		{name: "feed without a token", path: "/v1/team/1/fixtures.ics", wantStatus: http.StatusUnauthorized},
+		{name: "feed with an unknown token", path: "/v1/team/1/fixtures.ics?token=mf_unknown", wantStatus: http.StatusUnauthorized},
+		{name: "feed with a replaced token", path: "/v1/team/1/fixtures.ics?token=" + replaced, wantStatus: http.StatusUnauthorized},
+		{name: "feed with a revoked key's token", path: "/v1/team/1/fixtures.ics?token=" + revoked, wantStatus: http.StatusUnauthorized},
+		{name: "feed with the key as token", path: "/v1/team/1/fixtures.ics?token=" + key, wantStatus: http.StatusUnauthorized},
+		{name: "token only grants read:fixtures", path: "/v1/team/1/roster.ics?token=" + token, wantStatus: http.StatusForbidden},
+		{name: "token on another route", path: "/v1/fixtures?token=" + token, wantStatus: http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.path, nil))
+			assert.Equal(t, test.wantStatus, rec.Code)
+		})
+	}
+}
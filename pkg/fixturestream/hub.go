@@ -0,0 +1,157 @@
+// Package fixturestream fans out fixture changes, notified by Postgres, to the
+// clients streaming them
+package fixturestream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"mike/pkg/routes/fixtures/models"
+	"sync"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+// Channel is the Postgres notification channel the fixtures_notify_change trigger notifies
+const Channel = "fixture_changes"
+
+// subscriberBuffer is how many changes a subscriber can fall behind by before
+// it is dropped
+const subscriberBuffer = 64
+
+// Change is a fixture that was added or whose status, kickoff time or score changed
+type Change struct {
+	// Op is "insert" or "update"
+	Op             string        `json:"op"`
+	ID             int           `json:"id"`
+	SportID        int           `json:"sport_id"`
+	SeasonID       *int          `json:"season_id,omitempty"`
+	TeamID1        int           `json:"team_id_1"`
+	TeamID2        int           `json:"team_id_2"`
+	Status         models.Status `json:"status"`
+	DateTime       time.Time     `json:"date_time"`
+	HomeScore      *int          `json:"home_score"`
+	AwayScore      *int          `json:"away_score"`
+	PreviousStatus models.Status `json:"previous_status,omitempty"`
+	// PreviousDateTime is set on updates, and differs from DateTime when the kickoff moved
+	PreviousDateTime *time.Time `json:"previous_date_time,omitempty"`
+}
+
+// Filter selects the changes a subscriber receives; zero values match everything
+type Filter struct {
+	SportID int
+	TeamID  int
+}
+
+func (f Filter) Matches(change Change) bool {
+	if f.SportID != 0 && change.SportID != f.SportID {
+		return false
+	}
+	if f.TeamID != 0 && change.TeamID1 != f.TeamID && change.TeamID2 != f.TeamID {
+		return false
+	}
+	return true
+}
+
+type subscriber struct {
+	filter  Filter
+	changes chan Change
+}
+
+// Hub delivers each change to the subscribers whose filter it matches. A
+// subscriber that can't keep up is dropped, its channel closed, rather than
+// holding up the others.
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+	closed      bool
+}
+
+func NewHub() *Hub {
+	return &Hub{subscribers: make(map[*subscriber]struct{})}
+}
+
+// Subscribe returns a channel of the changes matching filter, and a function
+// to stop receiving them. The channel is closed when the subscriber is
+// dropped or the hub stops.
+func (h *Hub) Subscribe(filter Filter) (<-chan Change, func()) {
+	sub := &subscriber{filter: filter, changes: make(chan Change, subscriberBuffer)}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(sub.changes)
+		return sub.changes, func() {}
+	}
+	h.subscribers[sub] = struct{}{}
+	return sub.changes, func() { h.remove(sub) }
+}
+
+func (h *Hub) remove(sub *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subscribers[sub]; ok {
+		delete(h.subscribers, sub)
+		close(sub.changes)
+	}
+}
+
+// Publish delivers change to every matching subscriber without blocking
+func (h *Hub) Publish(change Change) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subscribers {
+		if !sub.filter.Matches(change) {
+			continue
+		}
+		select {
+		case sub.changes <- change:
+		default:
+			delete(h.subscribers, sub)
+			close(sub.changes)
+		}
+	}
+}
+
+// Close drops every subscriber; later subscriptions are closed straight away
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for sub := range h.subscribers {
+		delete(h.subscribers, sub)
+		close(sub.changes)
+	}
+}
+
+// Run listens for fixture change notifications and publishes them until ctx
+// is cancelled, then closes the hub. The listener reconnects on its own if
+// the connection drops.
+func (h *Hub) Run(ctx context.Context, db *bun.DB) error {
+	defer h.Close()
+
+	ln := pgdriver.NewListener(db)
+	defer ln.Close()
+	if err := ln.Listen(ctx, Channel); err != nil {
+		return err
+	}
+	notifications := ln.CreateChannel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification, ok := <-notifications:
+			if !ok {
+				return errors.New("fixture change listener closed")
+			}
+			var change Change
+			if err := json.Unmarshal([]byte(notification.Payload), &change); err != nil {
+				log.Printf("Ignoring fixture change notification %q: %v", notification.Payload, err)
+				continue
+			}
+			h.Publish(change)
+		}
+	}
+}
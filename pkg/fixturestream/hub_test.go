@@ -0,0 +1,65 @@
+package fixturestream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Filter_Matches(t *testing.T) {
+	change := Change{ID: 1, SportID: 5, TeamID1: 10, TeamID2: 20}
+
+	assert.True(t, Filter{}.Matches(change))
+	assert.True(t, Filter{SportID: 5}.Matches(change))
+	assert.True(t, Filter{TeamID: 20}.Matches(change), "away team")
+	assert.True(t, Filter{SportID: 5, TeamID: 10}.Matches(change))
+	assert.False(t, Filter{SportID: 4}.Matches(change))
+	assert.False(t, Filter{TeamID: 30}.Matches(change))
+	assert.False(t, Filter{SportID: 4, TeamID: 10}.Matches(change))
+}
+
+func Test_Hub_DeliversMatchingChanges(t *testing.T) {
+	hub := NewHub()
+	team, stopTeam := hub.Subscribe(Filter{TeamID: 10})
+	defer stopTeam()
+	all, stopAll := hub.Subscribe(Filter{})
+	defer stopAll()
+
+	hub.Publish(Change{ID: 1, TeamID1: 10, TeamID2: 20})
+	hub.Publish(Change{ID: 2, TeamID1: 30, TeamID2: 40})
+
+	assert.Equal(t, 1, (<-team).ID)
+	assert.Equal(t, 1, (<-all).ID)
+	assert.Equal(t, 2, (<-all).ID)
+	assert.Empty(t, team)
+}
+
+func Test_Hub_DropsSlowSubscribers(t *testing.T) {
+	hub := NewHub()
+	slow, stopSlow := hub.Subscribe(Filter{})
+	defer stopSlow()
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		hub.Publish(Change{ID: i})
+	}
+
+	received := 0
+	for range slow {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer, received, "the channel is closed once its buffer overflows")
+}
+
+func Test_Hub_CloseEndsSubscriptions(t *testing.T) {
+	hub := NewHub()
+	changes, stop := hub.Subscribe(Filter{})
+	hub.Close()
+	_, open := <-changes
+	assert.False(t, open)
+	// Stopping after the hub closed the channel is safe
+	stop()
+
+	later, _ := hub.Subscribe(Filter{})
+	_, open = <-later
+	assert.False(t, open)
+}
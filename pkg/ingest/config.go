@@ -0,0 +1,60 @@
+package ingest
+
+import (
+	"encoding/json"
+	"fmt"
+	"mike/config"
+	"mike/pkg/providers"
+	"mike/pkg/providers/apifootball"
+	"os"
+
+	"github.com/uptrace/bun"
+)
+
+// CompetitionConfig is one competition to sync, as listed in the competitions file
+type CompetitionConfig struct {
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+	LeagueID int    `json:"league_id"`
+	Season   string `json:"season"`
+	SportID  int    `json:"sport_id"`
+}
+
+func (c CompetitionConfig) Competition() providers.Competition {
+	return providers.Competition{
+		LeagueID: c.LeagueID,
+		Season:   c.Season,
+		SportID:  c.SportID,
+		Name:     c.Name,
+	}
+}
+
+// Config is the contents of the competitions file
+type Config struct {
+	Competitions []CompetitionConfig `json:"competitions"`
+}
+
+// LoadConfig reads and validates a competitions file
+func LoadConfig(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading competitions file: %w", err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing competitions file %s: %w", path, err)
+	}
+	for i, c := range cfg.Competitions {
+		if c.Provider == "" || c.LeagueID <= 0 || c.Season == "" || c.SportID <= 0 {
+			return nil, fmt.Errorf("competition %d (%q) in %s needs a provider, league_id, season and sport_id", i, c.Name, path)
+		}
+	}
+	return &cfg, nil
+}
+
+// DefaultProviders returns every provider we can sync from, keyed by name
+func DefaultProviders(cfg *config.Config, db *bun.DB) map[string]providers.Provider {
+	return map[string]providers.Provider{
+		apifootball.Name: apifootball.New(cfg.API, providers.TeamResolver{DB: db}),
+	}
+}
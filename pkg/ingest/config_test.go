@@ -0,0 +1,66 @@
+package ingest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LoadConfig(t *testing.T) {
+	writeConfig := func(t *testing.T, contents string) string {
+		path := filepath.Join(t.TempDir(), "competitions.json")
+		assert.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+		return path
+	}
+
+	tests := []struct {
+		name          string
+		path          func(t *testing.T) string
+		expectedCount int
+		wantError     bool
+	}{
+		{
+			name:          "repository competitions file",
+			path:          func(t *testing.T) string { return "../../config/competitions.json" },
+			expectedCount: 4,
+		},
+		{
+			name: "valid competition",
+			path: func(t *testing.T) string {
+				return writeConfig(t, `{"competitions": [{"name": "Eredivisie", "provider": "api-football", "league_id": 88, "season": "2025", "sport_id": 5}]}`)
+			},
+			expectedCount: 1,
+		},
+		{
+			name: "competition without a season",
+			path: func(t *testing.T) string {
+				return writeConfig(t, `{"competitions": [{"name": "Eredivisie", "provider": "api-football", "league_id": 88, "sport_id": 5}]}`)
+			},
+			wantError: true,
+		},
+		{
+			name:      "invalid JSON",
+			path:      func(t *testing.T) string { return writeConfig(t, `{"competitions": [`) },
+			wantError: true,
+		},
+		{
+			name:      "missing file",
+			path:      func(t *testing.T) string { return filepath.Join(t.TempDir(), "missing.json") },
+			wantError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg, err := LoadConfig(test.path(t))
+			if test.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, cfg.Competitions, test.expectedCount)
+		})
+	}
+}
@@ -0,0 +1,298 @@
+// Package ingest saves teams and fixtures fetched from a data provider
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mike/pkg/providers"
+	competitionModels "mike/pkg/routes/competitions/models"
+	fixtureModels "mike/pkg/routes/fixtures/models"
+	teamModels "mike/pkg/routes/teams/models"
+	venueModels "mike/pkg/routes/venues/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Summary reports what a sync did with the records the provider returned
+type Summary struct {
+	// RunID is the sync_runs row recording this sync
+	RunID int `json:"run_id"`
+	// Kind is what was synced, "teams" or "fixtures"
+	Kind        string                `json:"kind"`
+	Provider    string                `json:"provider"`
+	Competition providers.Competition `json:"competition"`
+	Inserted    int                   `json:"inserted"`
+	Updated     int                   `json:"updated"`
+	Unchanged   int                   `json:"unchanged"`
+	Skipped     []providers.Skip      `json:"skipped"`
+}
+
+func (s Summary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s league %d season %s: %d inserted, %d updated, %d unchanged, %d skipped",
+		s.Provider, s.Kind, s.Competition.LeagueID, s.Competition.Season, s.Inserted, s.Updated, s.Unchanged, len(s.Skipped))
+	for _, skip := range s.Skipped {
+		fmt.Fprintf(&b, "\n  skipped %s: %s", skip.ExternalID, skip.Reason)
+	}
+	return b.String()
+}
+
+// SyncTeams fetches a competition's teams from p and saves any that are new.
+// Teams are resolved with providers.TeamResolver first, so a team already
+// stored under another name or by another provider isn't added twice; one that
+// only resembles a stored team is skipped until its review is resolved.
+func SyncTeams(ctx context.Context, db *bun.DB, p providers.Provider, competition providers.Competition) (Summary, error) {
+	return recordRun(ctx, db, Summary{Kind: "teams", Provider: p.Name(), Competition: competition}, func(summary *Summary) error {
+		teams, err := p.ListTeams(ctx, competition)
+		if err != nil {
+			return fmt.Errorf("listing %s teams: %w", p.Name(), err)
+		}
+		teams, err = resolveTeams(ctx, db, p, competition, teams, summary)
+		if err != nil {
+			return err
+		}
+
+		if err := saveTeamVenues(ctx, db, teams); err != nil {
+			return fmt.Errorf("saving venues: %w", err)
+		}
+
+		season, err := ensureSeason(ctx, db, p, competition)
+		if err != nil {
+			return err
+		}
+		teamIDs := make([]int, 0, len(teams))
+		for _, team := range teams {
+			teamIDs = append(teamIDs, team.ID)
+		}
+		if err := competitionModels.AddSeasonTeams(ctx, db, season.ID, teamIDs); err != nil {
+			return fmt.Errorf("saving season teams: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// resolveTeams matches the provider's teams to stored teams, inserting those
+// with no match, and returns the teams that were matched or inserted with
+// their stored ids. Teams waiting on a review are added to summary.Skipped.
+func resolveTeams(ctx context.Context, db *bun.DB, p providers.Provider, competition providers.Competition, teams []teamModels.Team, summary *Summary) ([]teamModels.Team, error) {
+	resolver := providers.TeamResolver{DB: db}
+	refOf := func(team teamModels.Team) providers.TeamRef {
+		ref := providers.TeamRef{SportID: competition.SportID, Provider: p.Name(), Name: team.Name}
+		if team.ApiID != 0 {
+			ref.ExternalID = strconv.Itoa(team.ApiID)
+		}
+		return ref
+	}
+
+	var resolved, missing []teamModels.Team
+	for _, team := range teams {
+		stored, err := resolver.LookupTeam(ctx, refOf(team))
+		switch {
+		case err == nil:
+			team.ID = stored.ID
+			resolved = append(resolved, team)
+		case errors.Is(err, providers.ErrTeamInReview):
+			summary.Skipped = append(summary.Skipped, providers.Skip{ExternalID: refOf(team).ExternalID, Reason: fmt.Sprintf("team %s: %v", team.Name, err)})
+		case errors.Is(err, providers.ErrTeamNotFound):
+			missing = append(missing, team)
+		default:
+			return nil, fmt.Errorf("resolving team %s: %w", team.Name, err)
+		}
+	}
+	summary.Unchanged = len(resolved)
+
+	inserted, err := teamModels.InsertTeams(db, missing)
+	if err != nil {
+		return nil, fmt.Errorf("saving teams: %w", err)
+	}
+	summary.Inserted = inserted
+	// Resolving a new team by its name records the provider's id as its alias
+	for _, team := range missing {
+		stored, err := resolver.LookupTeam(ctx, refOf(team))
+		if err != nil {
+			return nil, fmt.Errorf("resolving new team %s: %w", team.Name, err)
+		}
+		team.ID = stored.ID
+		resolved = append(resolved, team)
+	}
+	return resolved, nil
+}
+
+// SyncFixtures fetches a competition's fixtures from p and upserts them. Records
+// the provider couldn't convert are skipped, not fatal, and are saved to
+// ingestion_skips.
+func SyncFixtures(ctx context.Context, db *bun.DB, p providers.Provider, competition providers.Competition) (Summary, error) {
+	return syncFixtures(ctx, db, p, competition, func() ([]fixtureModels.Fixture, []providers.Skip, error) {
+		return p.ListFixtures(ctx, competition)
+	})
+}
+
+// SyncFixturesBetween is SyncFixtures for fixtures kicking off between from and
+// to, including those that have since finished. Providers that can't list a
+// window sync the whole competition instead.
+func SyncFixturesBetween(ctx context.Context, db *bun.DB, p providers.Provider, competition providers.Competition, from, to time.Time) (Summary, error) {
+	lister, ok := p.(providers.WindowLister)
+	if !ok {
+		return SyncFixtures(ctx, db, p, competition)
+	}
+	return syncFixtures(ctx, db, p, competition, func() ([]fixtureModels.Fixture, []providers.Skip, error) {
+		return lister.ListFixturesBetween(ctx, competition, from, to)
+	})
+}
+
+func syncFixtures(ctx context.Context, db *bun.DB, p providers.Provider, competition providers.Competition, list func() ([]fixtureModels.Fixture, []providers.Skip, error)) (Summary, error) {
+	return recordRun(ctx, db, Summary{Kind: "fixtures", Provider: p.Name(), Competition: competition}, func(summary *Summary) error {
+		fixtures, skipped, err := list()
+		if err != nil {
+			return fmt.Errorf("listing %s fixtures: %w", p.Name(), err)
+		}
+		summary.Skipped = skipped
+
+		season, err := ensureSeason(ctx, db, p, competition)
+		if err != nil {
+			return err
+		}
+		for i := range fixtures {
+			fixtures[i].SeasonID = season.ID
+		}
+		if err := linkFixtureVenues(ctx, db, fixtures); err != nil {
+			return fmt.Errorf("saving venues: %w", err)
+		}
+
+		result, err := fixtureModels.UpsertFixtures(db, fixtures)
+		if err != nil {
+			return fmt.Errorf("saving fixtures: %w", err)
+		}
+		summary.Inserted = result.Inserted
+		summary.Updated = result.Updated
+		summary.Unchanged = result.Unchanged
+		for _, rejected := range result.Rejected {
+			summary.Skipped = append(summary.Skipped, providers.Skip{
+				ExternalID: rejected.ExternalID,
+				Reason:     fmt.Sprintf("status change %s -> %s not allowed, kept %s", rejected.From, rejected.To, rejected.From),
+			})
+		}
+
+		return nil
+	})
+}
+
+// saveTeamVenues saves the home venues that came with teams and links the teams to them
+func saveTeamVenues(ctx context.Context, db *bun.DB, teams []teamModels.Team) error {
+	var venues []venueModels.Venue
+	for _, team := range teams {
+		if team.Venue != nil {
+			venues = append(venues, *team.Venue)
+		}
+	}
+	ids, err := venueModels.UpsertVenues(ctx, db, venues)
+	if err != nil {
+		return err
+	}
+	venueByTeamID := make(map[int]int, len(teams))
+	for _, team := range teams {
+		if team.Venue == nil {
+			continue
+		}
+		if id, ok := ids[venueModels.VenueKey{Provider: team.Venue.Provider, ExternalID: team.Venue.ExternalID}]; ok {
+			venueByTeamID[team.ID] = id
+		}
+	}
+	return teamModels.SetTeamVenues(ctx, db, venueByTeamID)
+}
+
+// linkFixtureVenues saves the venues that came with fixtures and sets their VenueID
+func linkFixtureVenues(ctx context.Context, db *bun.DB, fixtures []fixtureModels.Fixture) error {
+	var venues []venueModels.Venue
+	for _, fixture := range fixtures {
+		if fixture.Venue != nil {
+			venues = append(venues, *fixture.Venue)
+		}
+	}
+	ids, err := venueModels.UpsertVenues(ctx, db, venues)
+	if err != nil {
+		return err
+	}
+	for i, fixture := range fixtures {
+		if fixture.Venue == nil {
+			continue
+		}
+		if id, ok := ids[venueModels.VenueKey{Provider: fixture.Venue.Provider, ExternalID: fixture.Venue.ExternalID}]; ok {
+			fixtures[i].VenueID = id
+		}
+	}
+	return nil
+}
+
+// ensureSeason returns the stored season the competition's records belong to
+func ensureSeason(ctx context.Context, db *bun.DB, p providers.Provider, competition providers.Competition) (competitionModels.Season, error) {
+	name := competition.Name
+	if name == "" {
+		name = fmt.Sprintf("%s league %d", p.Name(), competition.LeagueID)
+	}
+	season, err := competitionModels.EnsureSeason(ctx, db, competitionModels.Competition{
+		SportID:    competition.SportID,
+		Name:       name,
+		Provider:   p.Name(),
+		ExternalID: strconv.Itoa(competition.LeagueID),
+	}, competition.Season)
+	if err != nil {
+		return season, fmt.Errorf("saving competition season: %w", err)
+	}
+	return season, nil
+}
+
+// recordRun runs sync, filling in summary, and records it in sync_runs along
+// with the rows it skipped. Skips are saved whether or not the sync succeeded,
+// as a failed run is the one most likely to need them.
+func recordRun(ctx context.Context, db *bun.DB, summary Summary, sync func(summary *Summary) error) (Summary, error) {
+	run, err := StartRun(ctx, db, summary)
+	if err != nil {
+		return summary, fmt.Errorf("recording sync run: %w", err)
+	}
+	summary.RunID = run.ID
+
+	syncErr := sync(&summary)
+	// Record the outcome even when the sync was cancelled
+	ctx = context.WithoutCancel(ctx)
+	if err := SaveSkips(ctx, db, summary); err != nil {
+		syncErr = errors.Join(syncErr, fmt.Errorf("saving skipped rows: %w", err))
+	}
+	if err := FinishRun(ctx, db, run, summary, syncErr); err != nil {
+		return summary, errors.Join(syncErr, fmt.Errorf("recording sync run: %w", err))
+	}
+	return summary, syncErr
+}
+
+// SyncCompetitions syncs teams and then fixtures for each competition, using the
+// provider named in its config. A failing competition doesn't stop the others;
+// their errors are joined and returned with the summaries of what succeeded.
+func SyncCompetitions(ctx context.Context, db *bun.DB, registry map[string]providers.Provider, competitions []CompetitionConfig) ([]Summary, error) {
+	var summaries []Summary
+	var errs []error
+	for _, c := range competitions {
+		p, ok := registry[c.Provider]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: unknown provider %q", c.Name, c.Provider))
+			continue
+		}
+		teams, err := SyncTeams(ctx, db, p, c.Competition())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.Name, err))
+			continue
+		}
+		summaries = append(summaries, teams)
+		fixtures, err := SyncFixtures(ctx, db, p, c.Competition())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.Name, err))
+			continue
+		}
+		summaries = append(summaries, fixtures)
+	}
+	return summaries, errors.Join(errs...)
+}
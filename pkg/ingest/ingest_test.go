@@ -0,0 +1,315 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"mike/config"
+	"mike/pkg/pagination"
+	"mike/pkg/providers"
+	"mike/pkg/providers/apifootball"
+	"mike/pkg/providers/fake"
+	competitionModels "mike/pkg/routes/competitions/models"
+	fixtureModels "mike/pkg/routes/fixtures/models"
+	teamModels "mike/pkg/routes/teams/models"
+	"mike/utils"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+// setupIngestTestData creates a sport to ingest into and returns its id and a cleanup function
+func setupIngestTestData(t *testing.T, db *bun.DB) (int, func()) {
+	truncate := func() {
+		_, _ = db.NewRaw("TRUNCATE TABLE fixtures CASCADE").Exec(context.Background())
+		_, _ = db.NewRaw("TRUNCATE TABLE teams CASCADE").Exec(context.Background())
+		_, _ = db.NewRaw("TRUNCATE TABLE sports CASCADE").Exec(context.Background())
+		_, _ = db.NewRaw("TRUNCATE TABLE sync_runs CASCADE").Exec(context.Background())
+		_, _ = db.NewRaw("TRUNCATE TABLE ingestion_skips").Exec(context.Background())
+	}
+	truncate()
+
+	sport := struct {
+		bun.BaseModel `bun:"sports"`
+		ID            int    `bun:"id,pk,autoincrement"`
+		Name          string `bun:"name"`
+		IsActive      bool   `bun:"is_active"`
+	}{Name: "Test Sport Ingest", IsActive: true}
+	_, err := db.NewInsert().Model(&sport).Returning("id").Exec(context.Background())
+	assert.NoError(t, err)
+
+	return sport.ID, truncate
+}
+
+// lookupTeam finds the stored team for a provider's team id, the way the importers do
+func lookupTeam(db *bun.DB, sportID int, provider string, apiID int) (teamModels.Team, error) {
+	resolver := providers.TeamResolver{DB: db}
+	return resolver.LookupTeam(context.Background(), providers.TeamRef{SportID: sportID, Provider: provider, ExternalID: strconv.Itoa(apiID)})
+}
+
+func Test_SyncTeamsAndFixtures(t *testing.T) {
+	db := utils.GetDatabase()
+	sportID, teardown := setupIngestTestData(t, db)
+	defer teardown()
+
+	ctx := context.Background()
+	competition := providers.Competition{LeagueID: 39, Season: "2025", SportID: sportID}
+	provider := &fake.Provider{
+		Teams: map[providers.Competition][]teamModels.Team{
+			competition: {
+				{Name: "Home FC", SportId: sportID, IsActive: true, ApiID: 1},
+				{Name: "Away FC", SportId: sportID, IsActive: true, ApiID: 2},
+			},
+		},
+	}
+
+	summary, err := SyncTeams(ctx, db, provider, competition)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, summary.Inserted)
+
+	home, err := lookupTeam(db, sportID, provider.Name(), 1)
+	assert.NoError(t, err)
+	away, err := lookupTeam(db, sportID, provider.Name(), 2)
+	assert.NoError(t, err)
+
+	kickoff := time.Date(2025, 8, 16, 14, 0, 0, 0, time.UTC)
+	provider.Fixtures = map[providers.Competition][]fixtureModels.Fixture{
+		competition: {{
+			SportID: sportID, TeamID1: home.ID, TeamID2: away.ID, DateTime: kickoff,
+			Status: fixtureModels.StatusScheduled, Provider: provider.Name(), ExternalID: "1",
+		}},
+	}
+	provider.Skipped = map[providers.Competition][]providers.Skip{
+		competition: {{ExternalID: "2", Reason: "home team 99 (Unknown FC): team not found"}},
+	}
+
+	summary, err = SyncFixtures(ctx, db, provider, competition)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Inserted)
+	assert.Len(t, summary.Skipped, 1)
+
+	// Syncing again leaves a single, unchanged fixture
+	summary, err = SyncFixtures(ctx, db, provider, competition)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, summary.Inserted)
+	assert.Equal(t, 1, summary.Unchanged)
+
+	count, err := db.NewSelect().Model((*fixtureModels.Fixture)(nil)).Where("sport_id = ?", sportID).Count(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	// Skipped rows are recorded on every sync
+	var skips []SkipRecord
+	err = db.NewSelect().Model(&skips).Where("provider = ? AND external_id = ?", provider.Name(), "2").OrderExpr("id ASC").Scan(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, skips, 2)
+	assert.Equal(t, summary.RunID, skips[1].SyncRunID)
+
+	// Every sync is recorded as a run, newest first
+	runs, err := ListSyncRuns(db, SyncRunFilter{Provider: provider.Name()}, pagination.Params{Limit: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, runs.Data, 3) {
+		latest := runs.Data[0]
+		assert.Equal(t, summary.RunID, latest.ID)
+		assert.Equal(t, "fixtures", latest.Kind)
+		assert.Equal(t, RunSucceeded, latest.Status)
+		assert.NotNil(t, latest.FinishedAt)
+		assert.Equal(t, 1, latest.Unchanged)
+		assert.Equal(t, 1, latest.Skipped)
+		assert.Equal(t, "teams", runs.Data[2].Kind)
+		assert.Equal(t, 2, runs.Data[2].Inserted)
+	}
+}
+
+func Test_SyncTeams_ResolvesTeamsFromOtherProviders(t *testing.T) {
+	db := utils.GetDatabase()
+	sportID, teardown := setupIngestTestData(t, db)
+	defer teardown()
+
+	ctx := context.Background()
+	competition := providers.Competition{LeagueID: 39, Season: "2025", SportID: sportID}
+	first := &fake.Provider{
+		ProviderName: "first",
+		Teams: map[providers.Competition][]teamModels.Team{
+			competition: {
+				{Name: "Home FC", SportId: sportID, IsActive: true, ApiID: 1},
+				{Name: "Away FC", SportId: sportID, IsActive: true, ApiID: 2},
+			},
+		},
+	}
+	_, err := SyncTeams(ctx, db, first, competition)
+	assert.NoError(t, err)
+	home, err := lookupTeam(db, sportID, first.Name(), 1)
+	assert.NoError(t, err)
+	away, err := lookupTeam(db, sportID, first.Name(), 2)
+	assert.NoError(t, err)
+
+	second := &fake.Provider{
+		ProviderName: "second",
+		Teams: map[providers.Competition][]teamModels.Team{
+			competition: {
+				{Name: "Home", SportId: sportID, IsActive: true, ApiID: 10},
+				{Name: "Aways FC", SportId: sportID, IsActive: true, ApiID: 20},
+				{Name: "Visitors", SportId: sportID, IsActive: true, ApiID: 30},
+			},
+		},
+	}
+	summary, err := SyncTeams(ctx, db, second, competition)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Unchanged, "Home is Home FC without the suffix")
+	assert.Equal(t, 1, summary.Inserted, "Visitors is new")
+	if assert.Len(t, summary.Skipped, 1) {
+		assert.Equal(t, "20", summary.Skipped[0].ExternalID)
+		assert.Contains(t, summary.Skipped[0].Reason, "resembles Away FC")
+	}
+
+	// The name match is remembered against the second provider's id
+	resolver := providers.TeamResolver{DB: db}
+	team, err := resolver.LookupTeam(ctx, providers.TeamRef{SportID: sportID, Provider: "second", ExternalID: "10"})
+	assert.NoError(t, err)
+	assert.Equal(t, home.ID, team.ID)
+
+	reviews, err := teamModels.ListAliasReviews(db, teamModels.ReviewPending, pagination.Params{Limit: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, reviews.Data, 1) {
+		assert.Equal(t, "Aways FC", reviews.Data[0].Name)
+		assert.Equal(t, away.ID, reviews.Data[0].CandidateTeamID)
+
+		_, err = teamModels.ApproveAliasReview(db, reviews.Data[0].ID, away.ID)
+		assert.NoError(t, err)
+	}
+
+	// Once approved, every team resolves and nothing new is added
+	summary, err = SyncTeams(ctx, db, second, competition)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, summary.Inserted)
+	assert.Equal(t, 3, summary.Unchanged)
+	assert.Empty(t, summary.Skipped)
+
+	count, err := db.NewSelect().Model((*teamModels.Team)(nil)).Where("sport_id = ?", sportID).Count(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func Test_SyncFixtures_ReturnsProviderErrors(t *testing.T) {
+	db := utils.GetDatabase()
+	provider := &fake.Provider{Err: errors.New("provider unavailable")}
+
+	summary, err := SyncFixtures(context.Background(), db, provider, providers.Competition{LeagueID: 39, Season: "2025"})
+	assert.ErrorContains(t, err, "provider unavailable")
+
+	// The failure is recorded against the run
+	var run SyncRun
+	err = db.NewSelect().Model(&run).Where("id = ?", summary.RunID).Scan(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, RunFailed, run.Status)
+	if assert.NotNil(t, run.Error) {
+		assert.Contains(t, *run.Error, "provider unavailable")
+	}
+	_, _ = db.NewRaw("TRUNCATE TABLE sync_runs CASCADE").Exec(context.Background())
+}
+
+func Test_SyncFixtures_SavesSkipsWhenSyncFails(t *testing.T) {
+	db := utils.GetDatabase()
+	sportID, teardown := setupIngestTestData(t, db)
+	defer teardown()
+
+	ctx := context.Background()
+	competition := providers.Competition{LeagueID: 39, Season: "2025", SportID: sportID}
+	// Teams that don't exist fail the save, after the provider has skipped a row
+	provider := &fake.Provider{
+		Fixtures: map[providers.Competition][]fixtureModels.Fixture{
+			competition: {{
+				SportID: sportID, TeamID1: -1, TeamID2: -2, DateTime: time.Date(2025, 8, 16, 14, 0, 0, 0, time.UTC),
+				Status: fixtureModels.StatusScheduled, Provider: "fake", ExternalID: "1",
+			}},
+		},
+		Skipped: map[providers.Competition][]providers.Skip{
+			competition: {{ExternalID: "2", Reason: "home team 99 (Unknown FC): team not found"}},
+		},
+	}
+
+	summary, err := SyncFixtures(ctx, db, provider, competition)
+	assert.ErrorContains(t, err, "saving fixtures")
+
+	var run SyncRun
+	assert.NoError(t, db.NewSelect().Model(&run).Where("id = ?", summary.RunID).Scan(ctx))
+	assert.Equal(t, RunFailed, run.Status)
+	assert.Equal(t, 1, run.Skipped)
+
+	var skips []SkipRecord
+	assert.NoError(t, db.NewSelect().Model(&skips).Where("sync_run_id = ?", summary.RunID).Scan(ctx))
+	if assert.Len(t, skips, 1) {
+		assert.Equal(t, "2", skips[0].ExternalID)
+	}
+}
+
+// Test_SyncCompetitions_ReplaysAPIFootball runs the whole API-Football ingest
+// path, from raw payloads to stored fixtures, against responses recorded in testdata
+func Test_SyncCompetitions_ReplaysAPIFootball(t *testing.T) {
+	db := utils.GetDatabase()
+	sportID, teardown := setupIngestTestData(t, db)
+	defer teardown()
+
+	ctx := context.Background()
+	registry := map[string]providers.Provider{
+		apifootball.Name: apifootball.New(&config.APIConfig{
+			FootballAPIURL:    "https://v3.football.api-sports.io",
+			FootballReplayDir: "../providers/apifootball/testdata",
+		}, providers.TeamResolver{DB: db}),
+	}
+	competitions := []CompetitionConfig{{Name: "Premier League", Provider: apifootball.Name, LeagueID: 39, Season: "2025", SportID: sportID}}
+
+	summaries, err := SyncCompetitions(ctx, db, registry, competitions)
+	assert.NoError(t, err)
+	if assert.Len(t, summaries, 2) {
+		assert.Equal(t, 4, summaries[0].Inserted)
+		assert.Equal(t, 3, summaries[1].Inserted)
+		assert.Len(t, summaries[1].Skipped, 1, "Sunderland isn't in the recorded teams")
+	}
+
+	liverpool, err := lookupTeam(db, sportID, apifootball.Name, 40)
+	assert.NoError(t, err)
+	fixtures, err := fixtureModels.GetFixturesByTeam(db, liverpool.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, fixtures, 2) {
+		assert.Equal(t, 4, *fixtures[0].HomeScore)
+		assert.Equal(t, 2, *fixtures[0].AwayScore)
+		assert.Len(t, fixtures[0].Scores, 2)
+		assert.Nil(t, fixtures[1].HomeScore, "not played yet")
+		if assert.NotNil(t, fixtures[0].Venue) {
+			assert.Equal(t, "Anfield", fixtures[0].Venue.Name)
+			assert.Equal(t, 61276, fixtures[0].Venue.Capacity, "filled in from the teams response")
+		}
+	}
+	team, err := teamModels.GetTeamDetails(db, liverpool.ID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, team.Venue) {
+		assert.Equal(t, "Anfield", team.Venue.Name)
+	}
+
+	// Teams and fixtures are linked to the competition's season
+	competitionsPage, err := competitionModels.GetCompetitions(db, sportID, pagination.Params{Limit: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, competitionsPage.Data, 1) {
+		assert.Equal(t, "Premier League", competitionsPage.Data[0].Name)
+		seasons, err := competitionModels.GetSeasons(db, competitionsPage.Data[0].ID, pagination.Params{Limit: 10})
+		assert.NoError(t, err)
+		if assert.Len(t, seasons.Data, 1) {
+			assert.Equal(t, "2025", seasons.Data[0].Name)
+			assert.Equal(t, seasons.Data[0].ID, fixtures[0].SeasonID)
+			members, err := db.NewSelect().Table("season_teams").Where("season_id = ?", seasons.Data[0].ID).Count(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, 4, members)
+		}
+	}
+
+	// Replaying the same recordings changes nothing
+	summaries, err = SyncCompetitions(ctx, db, registry, competitions)
+	assert.NoError(t, err)
+	if assert.Len(t, summaries, 2) {
+		assert.Equal(t, 3, summaries[1].Unchanged)
+	}
+}
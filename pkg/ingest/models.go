@@ -0,0 +1,142 @@
+package ingest
+
+import (
+	"context"
+	"mike/pkg/pagination"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Sync run statuses
+const (
+	RunRunning   = "running"
+	RunSucceeded = "succeeded"
+	RunFailed    = "failed"
+)
+
+// SyncRun records one ingestion run: what it synced, when, and how it ended
+type SyncRun struct {
+	bun.BaseModel `bun:"sync_runs"`
+	ID            int        `bun:"id,pk,autoincrement" json:"id"`
+	Provider      string     `bun:"provider" json:"provider"`
+	Kind          string     `bun:"kind" json:"kind"`
+	LeagueID      int        `bun:"league_id" json:"league_id"`
+	Season        string     `bun:"season" json:"season"`
+	Status        string     `bun:"status" json:"status"`
+	StartedAt     time.Time  `bun:"started_at" json:"started_at"`
+	FinishedAt    *time.Time `bun:"finished_at" json:"finished_at"`
+	Inserted      int        `bun:"inserted" json:"inserted"`
+	Updated       int        `bun:"updated" json:"updated"`
+	Unchanged     int        `bun:"unchanged" json:"unchanged"`
+	Skipped       int        `bun:"skipped" json:"skipped"`
+	Error         *string    `bun:"error" json:"error"`
+}
+
+// StartRun records the start of the sync described by summary
+func StartRun(ctx context.Context, db *bun.DB, summary Summary) (*SyncRun, error) {
+	run := &SyncRun{
+		Provider:  summary.Provider,
+		Kind:      summary.Kind,
+		LeagueID:  summary.Competition.LeagueID,
+		Season:    summary.Competition.Season,
+		Status:    RunRunning,
+		StartedAt: time.Now().UTC(),
+	}
+	if _, err := db.NewInsert().Model(run).Returning("id").Exec(ctx); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// FinishRun records the outcome of a run: its counts, and syncErr if it failed
+func FinishRun(ctx context.Context, db *bun.DB, run *SyncRun, summary Summary, syncErr error) error {
+	finished := time.Now().UTC()
+	run.FinishedAt = &finished
+	run.Inserted = summary.Inserted
+	run.Updated = summary.Updated
+	run.Unchanged = summary.Unchanged
+	run.Skipped = len(summary.Skipped)
+	run.Status = RunSucceeded
+	if syncErr != nil {
+		msg := syncErr.Error()
+		run.Status = RunFailed
+		run.Error = &msg
+	}
+	_, err := db.NewUpdate().Model(run).
+		Column("status", "finished_at", "inserted", "updated", "unchanged", "skipped", "error").
+		WherePK().
+		Exec(ctx)
+	return err
+}
+
+// SyncRunFilter narrows ListSyncRuns; zero fields match everything
+type SyncRunFilter struct {
+	Provider string
+	LeagueID int
+	Season   string
+	Status   string
+}
+
+func syncRunCursor(r SyncRun) pagination.Cursor {
+	return pagination.Cursor{ID: r.ID}
+}
+
+// ListSyncRuns returns sync runs matching filter, newest first
+func ListSyncRuns(db *bun.DB, filter SyncRunFilter, page pagination.Params) (pagination.Page[SyncRun], error) {
+	var runs []SyncRun
+	q := db.NewSelect().Model(&runs)
+	if filter.Provider != "" {
+		q = q.Where("provider = ?", filter.Provider)
+	}
+	if filter.LeagueID != 0 {
+		q = q.Where("league_id = ?", filter.LeagueID)
+	}
+	if filter.Season != "" {
+		q = q.Where("season = ?", filter.Season)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if page.Cursor != nil {
+		q = q.Where("id < ?", page.Cursor.ID)
+	}
+	err := q.OrderExpr("id DESC").Limit(page.Limit + 1).Scan(context.Background())
+	if err != nil {
+		return pagination.Page[SyncRun]{}, err
+	}
+	return pagination.NewPage(runs, page.Limit, syncRunCursor), nil
+}
+
+// SkipRecord is a provider record dropped during ingestion
+type SkipRecord struct {
+	bun.BaseModel `bun:"ingestion_skips"`
+	ID            int       `bun:"id,pk,autoincrement" json:"id"`
+	SyncRunID     int       `bun:"sync_run_id,nullzero" json:"sync_run_id,omitempty"`
+	Provider      string    `bun:"provider" json:"provider"`
+	LeagueID      int       `bun:"league_id" json:"league_id"`
+	Season        string    `bun:"season" json:"season"`
+	ExternalID    string    `bun:"external_id" json:"external_id"`
+	Reason        string    `bun:"reason" json:"reason"`
+	CreatedAt     time.Time `bun:"created_at,nullzero,default:current_timestamp" json:"created_at"`
+}
+
+// SaveSkips records the skipped rows of a sync summary
+func SaveSkips(ctx context.Context, db *bun.DB, summary Summary) error {
+	if len(summary.Skipped) == 0 {
+		return nil
+	}
+	records := make([]SkipRecord, 0, len(summary.Skipped))
+	for _, skip := range summary.Skipped {
+		records = append(records, SkipRecord{
+			SyncRunID:  summary.RunID,
+			Provider:   summary.Provider,
+			LeagueID:   summary.Competition.LeagueID,
+			Season:     summary.Competition.Season,
+			ExternalID: skip.ExternalID,
+			Reason:     skip.Reason,
+		})
+	}
+	_, err := db.NewInsert().Model(&records).Exec(ctx)
+	return err
+}
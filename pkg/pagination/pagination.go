@@ -0,0 +1,126 @@
+// Shared keyset pagination for list endpoints
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidLimit  = errors.New("invalid limit")
+)
+
+// Cursor marks the last row of a page. Time is only set for lists ordered by a
+// timestamp before the id (fixtures by date_time); id-ordered lists leave it nil.
+type Cursor struct {
+	Time *time.Time `json:"t,omitempty"`
+	ID   int        `json:"id"`
+}
+
+// Encode returns the opaque string form of the cursor handed to clients
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses a cursor previously produced by Encode
+func DecodeCursor(s string) (Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID <= 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// Params are the page size and starting point requested by a client. A zero
+// Limit fetches every row, for endpoints that still answer clients not asking
+// for pages with a bare array, as they did before they were paged.
+type Params struct {
+	Limit  int
+	Cursor *Cursor
+}
+
+// ParseParams validates raw limit and cursor values. An empty limit falls back
+// to DefaultLimit and an empty cursor starts from the first page.
+func ParseParams(limit string, cursor string) (Params, error) {
+	params := Params{Limit: DefaultLimit}
+	if limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 1 || l > MaxLimit {
+			return Params{}, ErrInvalidLimit
+		}
+		params.Limit = l
+	}
+	if cursor != "" {
+		c, err := DecodeCursor(cursor)
+		if err != nil {
+			return Params{}, err
+		}
+		params.Cursor = &c
+	}
+	return params, nil
+}
+
+// ParseTimeParams is ParseParams for lists ordered by a timestamp, where a
+// cursor without a time cannot be resumed from.
+func ParseTimeParams(limit string, cursor string) (Params, error) {
+	params, err := ParseParams(limit, cursor)
+	if err != nil {
+		return Params{}, err
+	}
+	if params.Cursor != nil && params.Cursor.Time == nil {
+		return Params{}, ErrInvalidCursor
+	}
+	return params, nil
+}
+
+// ErrorMessage describes a ParseParams error for API clients
+func ErrorMessage(err error) string {
+	if errors.Is(err, ErrInvalidLimit) {
+		return fmt.Sprintf("Invalid limit, must be between 1 and %d", MaxLimit)
+	}
+	return "Invalid cursor"
+}
+
+// Page is the response envelope shared by list endpoints. NextCursor is empty
+// on the last page.
+type Page[T any] struct {
+	Data       []T    `json:"data"`
+	NextCursor string `json:"next_cursor"`
+}
+
+// Paged reports whether a client asked for pages, with a limit or a cursor
+func Paged(limit string, cursor string) bool {
+	return limit != "" || cursor != ""
+}
+
+// NewPage builds a page from rows fetched with a limit of params.Limit+1; the
+// extra row, if present, only signals that another page follows. A limit of
+// zero keeps every row.
+func NewPage[T any](rows []T, limit int, cursorOf func(T) Cursor) Page[T] {
+	if rows == nil {
+		rows = []T{}
+	}
+	if limit == 0 || len(rows) <= limit {
+		return Page[T]{Data: rows}
+	}
+	rows = rows[:limit]
+	return Page[T]{
+		Data:       rows,
+		NextCursor: cursorOf(rows[len(rows)-1]).Encode(),
+	}
+}
@@ -0,0 +1,93 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CursorRoundTrip(t *testing.T) {
+	kickoff := time.Date(2025, 8, 16, 14, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{name: "id only", cursor: Cursor{ID: 42}},
+		{name: "time and id", cursor: Cursor{Time: &kickoff, ID: 7}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			decoded, err := DecodeCursor(test.cursor.Encode())
+			assert.NoError(t, err)
+			assert.Equal(t, test.cursor.ID, decoded.ID)
+			if test.cursor.Time == nil {
+				assert.Nil(t, decoded.Time)
+			} else {
+				assert.True(t, test.cursor.Time.Equal(*decoded.Time))
+			}
+		})
+	}
+}
+
+func Test_ParseParams(t *testing.T) {
+	valid := Cursor{ID: 3}.Encode()
+
+	tests := []struct {
+		name          string
+		limit         string
+		cursor        string
+		expectedLimit int
+		expectedErr   error
+	}{
+		{name: "defaults", expectedLimit: DefaultLimit},
+		{name: "explicit limit", limit: "10", expectedLimit: 10},
+		{name: "with cursor", limit: "5", cursor: valid, expectedLimit: 5},
+		{name: "zero limit", limit: "0", expectedErr: ErrInvalidLimit},
+		{name: "limit above maximum", limit: "1000", expectedErr: ErrInvalidLimit},
+		{name: "non numeric limit", limit: "ten", expectedErr: ErrInvalidLimit},
+		{name: "garbage cursor", cursor: "not-a-cursor", expectedErr: ErrInvalidCursor},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			params, err := ParseParams(test.limit, test.cursor)
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedLimit, params.Limit)
+			assert.Equal(t, test.cursor != "", params.Cursor != nil)
+		})
+	}
+}
+
+func Test_NewPage(t *testing.T) {
+	cursorOf := func(id int) Cursor { return Cursor{ID: id} }
+
+	page := NewPage([]int{1, 2, 3}, 2, cursorOf)
+	assert.Equal(t, []int{1, 2}, page.Data)
+	next, err := DecodeCursor(page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, next.ID)
+
+	last := NewPage([]int{4}, 2, cursorOf)
+	assert.Equal(t, []int{4}, last.Data)
+	assert.Empty(t, last.NextCursor)
+
+	empty := NewPage[int](nil, 2, cursorOf)
+	assert.NotNil(t, empty.Data)
+
+	all := NewPage([]int{1, 2, 3}, 0, cursorOf)
+	assert.Equal(t, []int{1, 2, 3}, all.Data)
+	assert.Empty(t, all.NextCursor)
+}
+
+func Test_Paged(t *testing.T) {
+	assert.False(t, Paged("", ""))
+	assert.True(t, Paged("10", ""))
+	assert.True(t, Paged("", Cursor{ID: 1}.Encode()))
+}
@@ -0,0 +1,253 @@
+package apifootball
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxAttempts = 4
+	defaultBaseBackoff = time.Second
+	maxBackoff         = 30 * time.Second
+	// The per-minute quota has no reset header, so an exhausted minute is waited out in full
+	minuteWindow = time.Minute
+)
+
+// ErrQuotaExhausted is returned once the account's daily request quota is used up
+var ErrQuotaExhausted = errors.New("api-football daily request quota exhausted")
+
+// retryableError is a failed request that may succeed if sent again, after
+// retryAfter when the API said how long to wait.
+type retryableError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+// quota tracks the request allowance reported in API-Football's rate limit
+// headers. A negative remaining count means the API hasn't reported it yet.
+type quota struct {
+	mu                sync.Mutex
+	dailyRemaining    int
+	minuteRemaining   int
+	minuteExhaustedAt time.Time
+}
+
+func newQuota() *quota {
+	return &quota{dailyRemaining: -1, minuteRemaining: -1}
+}
+
+// update records the allowance reported by a response received at now
+func (q *quota) update(header http.Header, now time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if v, err := strconv.Atoi(header.Get("X-RateLimit-Requests-Remaining")); err == nil {
+		q.dailyRemaining = v
+	}
+	if v, err := strconv.Atoi(header.Get("X-RateLimit-Remaining")); err == nil {
+		q.minuteRemaining = v
+		if v == 0 {
+			q.minuteExhaustedAt = now
+		}
+	}
+}
+
+// wait returns how long to hold off before the next request, or
+// ErrQuotaExhausted when no requests are left today.
+func (q *quota) wait(now time.Time) (time.Duration, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.dailyRemaining == 0 {
+		return 0, ErrQuotaExhausted
+	}
+	if q.minuteRemaining != 0 {
+		return 0, nil
+	}
+	if resetAt := q.minuteExhaustedAt.Add(minuteWindow); now.Before(resetAt) {
+		return resetAt.Sub(now), nil
+	}
+	q.minuteRemaining = -1
+	return 0, nil
+}
+
+// Option configures a Provider
+type Option func(*Provider)
+
+// WithHTTPClient replaces the default HTTP client
+func WithHTTPClient(client *http.Client) Option {
+	return func(p *Provider) {
+		p.httpClient = client
+	}
+}
+
+// WithRetry sets how many times a request is attempted and the first backoff
+// delay, which doubles on every retry.
+func WithRetry(maxAttempts int, baseBackoff time.Duration) Option {
+	return func(p *Provider) {
+		p.maxAttempts = maxAttempts
+		p.baseBackoff = baseBackoff
+	}
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// backoff is the delay before retrying after the given failed attempt
+func (p *Provider) backoff(attempt int) time.Duration {
+	delay := p.baseBackoff << (attempt - 1)
+	if delay > maxBackoff || delay <= 0 {
+		return maxBackoff
+	}
+	return delay
+}
+
+// getPages requests every page of an endpoint, handing each response body to
+// decode, which returns the paging information it contains.
+func (p *Provider) getPages(ctx context.Context, endpoint string, params url.Values, decode func(body []byte) (Paging, error)) error {
+	for page := 1; ; page++ {
+		pageParams := url.Values{}
+		for k, v := range params {
+			pageParams[k] = v
+		}
+		// Not every endpoint accepts a page parameter, so the first page is requested without one
+		if page > 1 {
+			pageParams.Set("page", strconv.Itoa(page))
+		}
+
+		body, err := p.get(ctx, endpoint, pageParams)
+		if err != nil {
+			return err
+		}
+		paging, err := decode(body)
+		if err != nil {
+			return fmt.Errorf("parsing JSON: %w", err)
+		}
+		if page >= paging.Total {
+			return nil
+		}
+	}
+}
+
+// get requests an API endpoint, waiting out the rate limit and retrying
+// failures that may be temporary, and returns the response body.
+func (p *Provider) get(ctx context.Context, endpoint string, params url.Values) ([]byte, error) {
+	reqURL := fmt.Sprintf("%s/%s?%s", p.baseURL, endpoint, params.Encode())
+	for attempt := 1; ; attempt++ {
+		wait, err := p.quota.wait(p.now())
+		if err != nil {
+			return nil, err
+		}
+		if wait > 0 {
+			if err := p.sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+		}
+
+		body, err := p.do(ctx, reqURL)
+		if err == nil {
+			return body, nil
+		}
+		var retryable *retryableError
+		if !errors.As(err, &retryable) || attempt >= p.maxAttempts || ctx.Err() != nil {
+			return nil, err
+		}
+		delay := p.backoff(attempt)
+		if retryable.retryAfter > 0 {
+			delay = retryable.retryAfter
+		}
+		if err := p.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// do sends a single request and classifies its failure, if any
+func (p *Provider) do(ctx context.Context, reqURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Add("X-RapidAPI-Key", p.apiKey)
+	req.Header.Add("X-RapidAPI-Host", apiHost)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, &retryableError{err: fmt.Errorf("making request: %w", err)}
+	}
+	defer resp.Body.Close()
+	p.quota.update(resp.Header, p.now())
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &retryableError{err: fmt.Errorf("reading response: %w", err)}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return nil, &retryableError{
+			err:        fmt.Errorf("API request failed with status: %d, response: %s", resp.StatusCode, string(body)),
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("API request failed with status: %d, response: %s", resp.StatusCode, string(body))
+	}
+	if err := checkAPIErrors(body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// checkAPIErrors reports the errors API-Football returns in the body of a 200 response
+func checkAPIErrors(body []byte) error {
+	var envelope struct {
+		Errors json.RawMessage `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("parsing JSON: %w", err)
+	}
+	// Successful responses carry an empty array
+	var apiErrors map[string]string
+	if err := json.Unmarshal(envelope.Errors, &apiErrors); err != nil || len(apiErrors) == 0 {
+		return nil
+	}
+	if msg, ok := apiErrors["rateLimit"]; ok {
+		return &retryableError{err: fmt.Errorf("API rate limit: %s", msg)}
+	}
+	if msg, ok := apiErrors["requests"]; ok {
+		return fmt.Errorf("%w: %s", ErrQuotaExhausted, msg)
+	}
+	return fmt.Errorf("API returned errors: %v", apiErrors)
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
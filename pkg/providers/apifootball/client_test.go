@@ -0,0 +1,169 @@
+package apifootball
+
+import (
+	"context"
+	"fmt"
+	"mike/pkg/providers"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testCompetition = providers.Competition{LeagueID: 39, Season: "2025", SportID: 5}
+
+// recordSleeps replaces the provider's sleep so tests run instantly, returning
+// the delays it was asked to wait.
+func recordSleeps(p *Provider) *[]time.Duration {
+	var slept []time.Duration
+	p.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+	return &slept
+}
+
+func fixturesPage(page, total, fixtureID int) string {
+	return fmt.Sprintf(`{
+		"errors": [],
+		"paging": {"current": %d, "total": %d},
+		"response": [{
+			"fixture": {"id": %d, "date": "2025-08-15T19:00:00+00:00", "status": {"short": "NS"}},
+			"teams": {"home": {"id": 40}, "away": {"id": 33}}
+		}]
+	}`, page, total, fixtureID)
+}
+
+func Test_ListFixtures_FollowsAllPages(t *testing.T) {
+	var requests atomic.Int32
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		page := 1
+		if p := r.URL.Query().Get("page"); p != "" {
+			page, _ = strconv.Atoi(p)
+		}
+		_, _ = w.Write([]byte(fixturesPage(page, 3, 1000+page)))
+	})
+
+	fixtures, _, err := provider.ListFixtures(context.Background(), testCompetition)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), requests.Load())
+	assert.Len(t, fixtures, 3)
+	assert.Equal(t, "1003", fixtures[2].ExternalID)
+}
+
+func Test_ListFixtures_RetriesServerErrorsWithBackoff(t *testing.T) {
+	var requests atomic.Int32
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(fixturesPage(1, 1, 1)))
+	})
+	slept := recordSleeps(provider)
+
+	fixtures, _, err := provider.ListFixtures(context.Background(), testCompetition)
+	assert.NoError(t, err)
+	assert.Len(t, fixtures, 1)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, *slept)
+}
+
+func Test_ListFixtures_HonoursRetryAfter(t *testing.T) {
+	var requests atomic.Int32
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(fixturesPage(1, 1, 1)))
+	})
+	slept := recordSleeps(provider)
+
+	_, _, err := provider.ListFixtures(context.Background(), testCompetition)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{7 * time.Second}, *slept)
+}
+
+func Test_ListFixtures_GivesUpAfterMaxAttempts(t *testing.T) {
+	var requests atomic.Int32
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	WithRetry(3, time.Millisecond)(provider)
+	recordSleeps(provider)
+
+	_, _, err := provider.ListFixtures(context.Background(), testCompetition)
+	assert.ErrorContains(t, err, "502")
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func Test_ListFixtures_RetriesRateLimitErrorsInBody(t *testing.T) {
+	var requests atomic.Int32
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			_, _ = w.Write([]byte(`{"errors": {"rateLimit": "Too many requests"}, "response": []}`))
+			return
+		}
+		_, _ = w.Write([]byte(fixturesPage(1, 1, 1)))
+	})
+	recordSleeps(provider)
+
+	fixtures, _, err := provider.ListFixtures(context.Background(), testCompetition)
+	assert.NoError(t, err)
+	assert.Len(t, fixtures, 1)
+}
+
+func Test_ListFixtures_DoesNotRetryAPIErrors(t *testing.T) {
+	var requests atomic.Int32
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(`{"errors": {"token": "Error/Missing application key"}, "response": []}`))
+	})
+	recordSleeps(provider)
+
+	_, _, err := provider.ListFixtures(context.Background(), testCompetition)
+	assert.ErrorContains(t, err, "Missing application key")
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func Test_ListFixtures_StopsWhenDailyQuotaIsExhausted(t *testing.T) {
+	var requests atomic.Int32
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("X-RateLimit-Requests-Remaining", "0")
+		_, _ = w.Write([]byte(fixturesPage(1, 2, 1)))
+	})
+
+	_, _, err := provider.ListFixtures(context.Background(), testCompetition)
+	assert.ErrorIs(t, err, ErrQuotaExhausted)
+	assert.Equal(t, int32(1), requests.Load(), "no request should be sent once the quota is used up")
+}
+
+func Test_ListFixtures_WaitsOutPerMinuteQuota(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		page := 1
+		if p := r.URL.Query().Get("page"); p != "" {
+			page, _ = strconv.Atoi(p)
+		}
+		remaining := "10"
+		if page == 1 {
+			remaining = "0"
+		}
+		w.Header().Set("X-RateLimit-Remaining", remaining)
+		_, _ = w.Write([]byte(fixturesPage(page, 2, page)))
+	})
+	now := time.Date(2025, 8, 15, 12, 0, 0, 0, time.UTC)
+	provider.now = func() time.Time { return now }
+	slept := recordSleeps(provider)
+
+	fixtures, _, err := provider.ListFixtures(context.Background(), testCompetition)
+	assert.NoError(t, err)
+	assert.Len(t, fixtures, 2)
+	assert.Equal(t, []time.Duration{minuteWindow}, *slept)
+}
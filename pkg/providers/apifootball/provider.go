@@ -0,0 +1,120 @@
+// Package apifootball implements providers.Provider for API-Football (api-sports.io)
+package apifootball
+
+import (
+	"context"
+	"encoding/json"
+	"mike/config"
+	"mike/pkg/providers"
+	fixtureModels "mike/pkg/routes/fixtures/models"
+	teamModels "mike/pkg/routes/teams/models"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Name identifies API-Football fixtures in the fixtures table
+const Name = "api-football"
+
+const apiHost = "v3.football.api-sports.io"
+
+type Provider struct {
+	baseURL     string
+	apiKey      string
+	httpClient  *http.Client
+	teams       providers.TeamLookup
+	quota       *quota
+	maxAttempts int
+	baseBackoff time.Duration
+	// Overridden in tests to avoid real waits
+	sleep func(ctx context.Context, d time.Duration) error
+	now   func() time.Time
+}
+
+var (
+	_ providers.Provider     = (*Provider)(nil)
+	_ providers.WindowLister = (*Provider)(nil)
+)
+
+// New creates an API-Football provider. teams maps API-Football team ids in
+// fixtures onto stored teams. cfg can point it at recorded responses, see
+// WithRecording and WithReplay.
+func New(cfg *config.APIConfig, teams providers.TeamLookup, opts ...Option) *Provider {
+	p := &Provider{
+		baseURL:     cfg.FootballAPIURL,
+		apiKey:      cfg.FootballAPIKey,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		teams:       teams,
+		quota:       newQuota(),
+		maxAttempts: defaultMaxAttempts,
+		baseBackoff: defaultBaseBackoff,
+		sleep:       sleepContext,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	switch {
+	case cfg.FootballReplayDir != "":
+		WithReplay(cfg.FootballReplayDir)(p)
+	case cfg.FootballRecordDir != "":
+		WithRecording(cfg.FootballRecordDir)(p)
+	}
+	return p
+}
+
+func (p *Provider) Name() string {
+	return Name
+}
+
+func (p *Provider) ListTeams(ctx context.Context, competition providers.Competition) ([]teamModels.Team, error) {
+	var teamsResp TeamsResponse
+	err := p.getPages(ctx, "teams", competitionParams(competition), func(body []byte) (Paging, error) {
+		var page TeamsResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return Paging{}, err
+		}
+		teamsResp.Response = append(teamsResp.Response, page.Response...)
+		return page.Paging, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return teamsResp.ToTeams(competition.SportID), nil
+}
+
+func (p *Provider) ListFixtures(ctx context.Context, competition providers.Competition) ([]fixtureModels.Fixture, []providers.Skip, error) {
+	return p.listFixtures(ctx, competition, competitionParams(competition))
+}
+
+func (p *Provider) ListFixturesBetween(ctx context.Context, competition providers.Competition, from, to time.Time) ([]fixtureModels.Fixture, []providers.Skip, error) {
+	params := competitionParams(competition)
+	params.Set("from", from.UTC().Format(time.DateOnly))
+	params.Set("to", to.UTC().Format(time.DateOnly))
+	params.Set("timezone", "UTC")
+	return p.listFixtures(ctx, competition, params)
+}
+
+func (p *Provider) listFixtures(ctx context.Context, competition providers.Competition, params url.Values) ([]fixtureModels.Fixture, []providers.Skip, error) {
+	var fixturesResp FixturesResponse
+	err := p.getPages(ctx, "fixtures", params, func(body []byte) (Paging, error) {
+		var page FixturesResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return Paging{}, err
+		}
+		fixturesResp.Response = append(fixturesResp.Response, page.Response...)
+		return page.Paging, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return fixturesResp.ToFixtures(ctx, p.teams, competition.SportID)
+}
+
+func competitionParams(competition providers.Competition) url.Values {
+	return url.Values{
+		"league": {strconv.Itoa(competition.LeagueID)},
+		"season": {competition.Season},
+	}
+}
@@ -0,0 +1,158 @@
+package apifootball
+
+import (
+	"context"
+	"mike/config"
+	"mike/pkg/providers"
+	fixtureModels "mike/pkg/routes/fixtures/models"
+	teamModels "mike/pkg/routes/teams/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubTeams resolves API-Football team ids from a map
+type stubTeams map[string]teamModels.Team
+
+func (s stubTeams) LookupTeam(ctx context.Context, ref providers.TeamRef) (teamModels.Team, error) {
+	team, ok := s[ref.ExternalID]
+	if !ok {
+		return teamModels.Team{}, providers.ErrTeamNotFound
+	}
+	return team, nil
+}
+
+const teamsPayload = `{
+	"get": "teams",
+	"parameters": {"league": "39", "season": "2025"},
+	"errors": [],
+	"results": 2,
+	"paging": {"current": 1, "total": 1},
+	"response": [
+		{"team": {"id": 33, "name": "Manchester United", "logo": "https://media.api-sports.io/football/teams/33.png"}},
+		{"team": {"id": 40, "name": "Liverpool", "logo": "https://media.api-sports.io/football/teams/40.png"}}
+	]
+}`
+
+const fixturesPayload = `{
+	"get": "fixtures",
+	"parameters": {"league": "39", "season": "2025"},
+	"errors": [],
+	"results": 1,
+	"paging": {"current": 1, "total": 1},
+	"response": [
+		{
+			"fixture": {"id": 1208021, "date": "2025-08-15T19:00:00+00:00", "status": {"long": "Match Finished", "short": "FT"}},
+			"league": {"id": 39, "name": "Premier League", "season": 2025},
+			"teams": {"home": {"id": 40, "name": "Liverpool"}, "away": {"id": 33, "name": "Manchester United"}},
+			"goals": {"home": 4, "away": 2},
+			"score": {
+				"halftime": {"home": 2, "away": 0},
+				"fulltime": {"home": 4, "away": 2},
+				"extratime": {"home": null, "away": null},
+				"penalty": {"home": null, "away": null}
+			}
+		}
+	]
+}`
+
+func newTestProvider(t *testing.T, handler http.HandlerFunc) *Provider {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return New(&config.APIConfig{FootballAPIURL: server.URL, FootballAPIKey: "test-key"}, stubTeams{
+		"33": {ID: 1, Name: "Manchester United"},
+		"40": {ID: 2, Name: "Liverpool"},
+	})
+}
+
+func Test_ListTeams(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/teams", r.URL.Path)
+		assert.Equal(t, "39", r.URL.Query().Get("league"))
+		assert.Equal(t, "2025", r.URL.Query().Get("season"))
+		assert.Equal(t, "test-key", r.Header.Get("X-RapidAPI-Key"))
+		_, _ = w.Write([]byte(teamsPayload))
+	})
+
+	teams, err := provider.ListTeams(context.Background(), providers.Competition{LeagueID: 39, Season: "2025", SportID: 5})
+	assert.NoError(t, err)
+	assert.Len(t, teams, 2)
+	assert.Equal(t, "Manchester United", teams[0].Name)
+	assert.Equal(t, 33, teams[0].ApiID)
+	assert.Equal(t, 5, teams[0].SportId)
+}
+
+func Test_ListFixtures(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/fixtures", r.URL.Path)
+		_, _ = w.Write([]byte(fixturesPayload))
+	})
+
+	fixtures, skipped, err := provider.ListFixtures(context.Background(), providers.Competition{LeagueID: 39, Season: "2025", SportID: 5})
+	assert.NoError(t, err)
+	assert.Empty(t, skipped)
+	assert.Len(t, fixtures, 1)
+	assert.Equal(t, 2, fixtures[0].TeamID1)
+	assert.Equal(t, 1, fixtures[0].TeamID2)
+	assert.Equal(t, Name, fixtures[0].Provider)
+	assert.Equal(t, "1208021", fixtures[0].ExternalID)
+	assert.Equal(t, "Liverpool", fixtures[0].Details.HomeTeam)
+	assert.Equal(t, fixtureModels.StatusFinished, fixtures[0].Status)
+	assert.Equal(t, "FT", fixtures[0].Details.ProviderStatus)
+	assert.Equal(t, 4, *fixtures[0].HomeScore)
+	assert.Equal(t, 2, *fixtures[0].AwayScore)
+	assert.Equal(t, []fixtureModels.FixtureScore{
+		{Period: "halftime", Sequence: 1, Home: 2, Away: 0},
+		{Period: "fulltime", Sequence: 2, Home: 4, Away: 2},
+	}, fixtures[0].Scores)
+}
+
+func Test_ListFixtures_LeavesScoreEmptyBeforeKickoff(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(fixturesPage(1, 1, 1)))
+	})
+
+	fixtures, _, err := provider.ListFixtures(context.Background(), testCompetition)
+	assert.NoError(t, err)
+	assert.Nil(t, fixtures[0].HomeScore)
+	assert.Nil(t, fixtures[0].AwayScore)
+	assert.Empty(t, fixtures[0].Scores)
+}
+
+func Test_ListTeams_ReturnsErrorOnFailedRequest(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	_, err := provider.ListTeams(context.Background(), providers.Competition{LeagueID: 39, Season: "2025", SportID: 5})
+	assert.Error(t, err)
+}
+
+func Test_ListFixtures_SkipsRowsThatCannotBeConverted(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"errors": [],
+			"paging": {"current": 1, "total": 1},
+			"response": [
+				{"fixture": {"id": 1, "date": "2025-08-15T19:00:00+00:00", "status": {"short": "NS"}}, "teams": {"home": {"id": 40}, "away": {"id": 33}}},
+				{"fixture": {"id": 2, "date": "2025-08-16T14:00:00+00:00", "status": {"short": "NS"}}, "teams": {"home": {"id": 99, "name": "Unknown FC"}, "away": {"id": 33}}},
+				{"fixture": {"id": 3, "date": "not a date", "status": {"short": "NS"}}, "teams": {"home": {"id": 40}, "away": {"id": 33}}},
+				{"fixture": {"id": 4, "date": "2025-08-17T14:00:00+00:00", "status": {"short": "XYZ"}}, "teams": {"home": {"id": 40}, "away": {"id": 33}}}
+			]
+		}`))
+	})
+
+	fixtures, skipped, err := provider.ListFixtures(context.Background(), providers.Competition{LeagueID: 39, Season: "2025", SportID: 5})
+	assert.NoError(t, err)
+	assert.Len(t, fixtures, 1)
+	assert.Equal(t, "1", fixtures[0].ExternalID)
+	assert.Len(t, skipped, 3)
+	assert.Equal(t, "2", skipped[0].ExternalID)
+	assert.Contains(t, skipped[0].Reason, "Unknown FC")
+	assert.Equal(t, "3", skipped[1].ExternalID)
+	assert.Contains(t, skipped[1].Reason, "date")
+	assert.Equal(t, "4", skipped[2].ExternalID)
+	assert.Contains(t, skipped[2].Reason, "XYZ")
+}
@@ -0,0 +1,118 @@
+package apifootball
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// WithRecording saves the raw body of every successful response under dir, in
+// the files WithReplay serves them back from.
+func WithRecording(dir string) Option {
+	return func(p *Provider) {
+		client := *p.httpClient
+		client.Transport = &recordingTransport{next: client.Transport, dir: dir}
+		p.httpClient = &client
+	}
+}
+
+// WithReplay serves responses from files saved by WithRecording instead of
+// calling the API. A request with no recording fails with a 404.
+func WithReplay(dir string) Option {
+	return func(p *Provider) {
+		client := *p.httpClient
+		client.Transport = &replayTransport{dir: dir}
+		p.httpClient = &client
+	}
+}
+
+// recordingFile names the file a request's response is recorded in, from its
+// endpoint and sorted query parameters, e.g. fixtures_league-39_season-2025.json
+func recordingFile(u *url.URL) string {
+	parts := []string{path.Base(u.Path)}
+	query := u.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		parts = append(parts, key+"-"+strings.Join(query[key], ","))
+	}
+	name := strings.Join(parts, "_")
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' {
+			return '-'
+		}
+		return r
+	}, name)
+	return name + ".json"
+}
+
+type recordingTransport struct {
+	next http.RoundTripper
+	dir  string
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil || resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	// API errors arrive with a 200 too, and aren't worth replaying
+	if checkAPIErrors(body) != nil {
+		return resp, nil
+	}
+
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating recording directory: %w", err)
+	}
+	file := filepath.Join(t.dir, recordingFile(req.URL))
+	if err := os.WriteFile(file, body, 0o644); err != nil {
+		return nil, fmt.Errorf("recording response: %w", err)
+	}
+	return resp, nil
+}
+
+type replayTransport struct {
+	dir string
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	file := filepath.Join(t.dir, recordingFile(req.URL))
+	status := http.StatusOK
+	body, err := os.ReadFile(file)
+	if err != nil {
+		status = http.StatusNotFound
+		body = []byte(fmt.Sprintf("no recording %s: %v", file, err))
+	}
+	return &http.Response{
+		Status:        http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
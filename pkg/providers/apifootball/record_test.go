@@ -0,0 +1,92 @@
+package apifootball
+
+import (
+	"context"
+	"mike/config"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var recordedTeams = stubTeams{
+	"33": {ID: 1, Name: "Manchester United"},
+	"40": {ID: 2, Name: "Liverpool"},
+	"42": {ID: 3, Name: "Arsenal"},
+	"49": {ID: 4, Name: "Chelsea"},
+}
+
+func newReplayProvider(dir string) *Provider {
+	return New(&config.APIConfig{FootballAPIURL: "https://" + apiHost, FootballReplayDir: dir}, recordedTeams)
+}
+
+func Test_Replay_ServesRecordedPayloads(t *testing.T) {
+	provider := newReplayProvider("testdata")
+
+	teams, err := provider.ListTeams(context.Background(), testCompetition)
+	assert.NoError(t, err)
+	assert.Len(t, teams, 4)
+	if assert.NotNil(t, teams[0].Venue) {
+		assert.Equal(t, "Old Trafford", teams[0].Venue.Name)
+		assert.Equal(t, "556", teams[0].Venue.ExternalID)
+	}
+
+	fixtures, skipped, err := provider.ListFixtures(context.Background(), testCompetition)
+	assert.NoError(t, err)
+	assert.Len(t, fixtures, 3, "both recorded pages are replayed")
+	if assert.NotNil(t, fixtures[0].Venue) {
+		assert.Equal(t, "550", fixtures[0].Venue.ExternalID)
+	}
+	if assert.Len(t, skipped, 1) {
+		assert.Equal(t, "1378982", skipped[0].ExternalID)
+	}
+}
+
+func Test_Replay_FailsWithoutRecording(t *testing.T) {
+	provider := newReplayProvider(t.TempDir())
+
+	_, _, err := provider.ListFixtures(context.Background(), testCompetition)
+	assert.ErrorContains(t, err, "no recording")
+}
+
+func Test_Recording_CanBeReplayed(t *testing.T) {
+	dir := t.TempDir()
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/teams" {
+			_, _ = w.Write([]byte(teamsPayload))
+			return
+		}
+		_, _ = w.Write([]byte(fixturesPayload))
+	})
+	WithRecording(dir)(provider)
+
+	recordedFixtures, _, err := provider.ListFixtures(context.Background(), testCompetition)
+	assert.NoError(t, err)
+	_, err = provider.ListTeams(context.Background(), testCompetition)
+	assert.NoError(t, err)
+
+	body, err := os.ReadFile(filepath.Join(dir, "fixtures_league-39_season-2025.json"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, fixturesPayload, string(body))
+	assert.FileExists(t, filepath.Join(dir, "teams_league-39_season-2025.json"))
+
+	replayed, _, err := newReplayProvider(dir).ListFixtures(context.Background(), testCompetition)
+	assert.NoError(t, err)
+	assert.Equal(t, recordedFixtures, replayed)
+}
+
+func Test_Recording_SkipsAPIErrors(t *testing.T) {
+	dir := t.TempDir()
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errors": {"token": "Error/Missing application key"}, "response": []}`))
+	})
+	WithRecording(dir)(provider)
+
+	_, err := provider.ListTeams(context.Background(), testCompetition)
+	assert.Error(t, err)
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
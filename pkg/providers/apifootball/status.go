@@ -0,0 +1,41 @@
+package apifootball
+
+import (
+	"fmt"
+	"mike/pkg/providers"
+	fixtureModels "mike/pkg/routes/fixtures/models"
+)
+
+// statuses maps API-Football's short status codes onto canonical fixture statuses.
+// See https://www.api-football.com/documentation-v3#tag/Fixtures/operation/get-fixtures
+var statuses = map[string]fixtureModels.Status{
+	"TBD":  fixtureModels.StatusScheduled, // time to be defined
+	"NS":   fixtureModels.StatusScheduled,
+	"1H":   fixtureModels.StatusLive,
+	"2H":   fixtureModels.StatusLive,
+	"ET":   fixtureModels.StatusLive,
+	"P":    fixtureModels.StatusLive, // penalty shootout in progress
+	"LIVE": fixtureModels.StatusLive,
+	"HT":   fixtureModels.StatusHalftime,
+	"BT":   fixtureModels.StatusHalftime, // break before extra time
+	"FT":   fixtureModels.StatusFinished,
+	"AET":  fixtureModels.StatusFinished,
+	"PEN":  fixtureModels.StatusFinished,
+	"AWD":  fixtureModels.StatusFinished, // result awarded
+	"WO":   fixtureModels.StatusFinished, // walkover
+	"SUSP": fixtureModels.StatusSuspended,
+	"INT":  fixtureModels.StatusSuspended, // interrupted
+	"PST":  fixtureModels.StatusPostponed,
+	"CANC": fixtureModels.StatusCancelled,
+	"ABD":  fixtureModels.StatusAbandoned,
+}
+
+// toStatus maps an API-Football status code, returning an error wrapping
+// providers.ErrUnknownStatus for codes we don't know
+func toStatus(code string) (fixtureModels.Status, error) {
+	status, ok := statuses[code]
+	if !ok {
+		return "", fmt.Errorf("status %q: %w", code, providers.ErrUnknownStatus)
+	}
+	return status, nil
+}
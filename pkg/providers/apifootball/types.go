@@ -0,0 +1,256 @@
+package apifootball
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mike/pkg/providers"
+	fixtureModels "mike/pkg/routes/fixtures/models"
+	teamModels "mike/pkg/routes/teams/models"
+	venueModels "mike/pkg/routes/venues/models"
+	"strconv"
+	"time"
+)
+
+// Paging reports which page of a multi-page result a response holds
+type Paging struct {
+	Current int `json:"current"`
+	Total   int `json:"total"`
+}
+
+// Raw API response structures for teams belonging to a league and season
+type TeamsResponse struct {
+	Get        string `json:"get"`
+	Parameters struct {
+		League string `json:"league"`
+		Season string `json:"season"`
+	} `json:"parameters"`
+	// Errors is an empty array on success and an object keyed by error type otherwise
+	Errors   json.RawMessage `json:"errors"`
+	Results  int             `json:"results"`
+	Paging   Paging          `json:"paging"`
+	Response []TeamData      `json:"response"`
+}
+
+type TeamData struct {
+	Team struct {
+		ID       int    `json:"id"`
+		Name     string `json:"name"`
+		Code     string `json:"code"`
+		Country  string `json:"country"`
+		Founded  int    `json:"founded"`
+		National bool   `json:"national"`
+		Logo     string `json:"logo"`
+	} `json:"team"`
+	Venue struct {
+		ID       int    `json:"id"`
+		Name     string `json:"name"`
+		Address  string `json:"address"`
+		City     string `json:"city"`
+		Capacity int    `json:"capacity"`
+		Surface  string `json:"surface"`
+		Image    string `json:"image"`
+	} `json:"venue"`
+}
+
+// Raw API response structures for fixtures belonging to a league and season
+type FixturesResponse struct {
+	Get        string            `json:"get"`
+	Parameters map[string]string `json:"parameters"`
+	Errors     json.RawMessage   `json:"errors"`
+	Results    int               `json:"results"`
+	Paging     Paging            `json:"paging"`
+	Response   []FixtureItem     `json:"response"`
+}
+
+type FixtureItem struct {
+	Fixture struct {
+		ID        int    `json:"id"`
+		Timezone  string `json:"timezone"`
+		Date      string `json:"date"` // ISO8601
+		Timestamp int64  `json:"timestamp"`
+		Venue     struct {
+			ID   *int   `json:"id"`
+			Name string `json:"name"`
+			City string `json:"city"`
+		} `json:"venue"`
+		Status struct {
+			Long    string      `json:"long"`
+			Short   string      `json:"short"`
+			Elapsed *int        `json:"elapsed"`
+			Extra   interface{} `json:"extra"`
+		} `json:"status"`
+	} `json:"fixture"`
+	League struct {
+		ID     int    `json:"id"`
+		Name   string `json:"name"`
+		Season int    `json:"season"`
+	} `json:"league"`
+	Teams struct {
+		Home struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		} `json:"home"`
+		Away struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		} `json:"away"`
+	} `json:"teams"`
+	// Goals is the running or final score, null before kickoff
+	Goals ScorePair `json:"goals"`
+	Score struct {
+		Halftime  ScorePair `json:"halftime"`
+		Fulltime  ScorePair `json:"fulltime"`
+		Extratime ScorePair `json:"extratime"`
+		Penalty   ScorePair `json:"penalty"`
+	} `json:"score"`
+}
+
+// ScorePair is a home and away score, null for a period that hasn't been played
+type ScorePair struct {
+	Home *int `json:"home"`
+	Away *int `json:"away"`
+}
+
+// venue is where the fixture is played, when the API knows
+func (fixture FixtureItem) venue() *venueModels.Venue {
+	if fixture.Fixture.Venue.ID == nil {
+		return nil
+	}
+	return &venueModels.Venue{
+		Name:       fixture.Fixture.Venue.Name,
+		City:       fixture.Fixture.Venue.City,
+		Provider:   Name,
+		ExternalID: strconv.Itoa(*fixture.Fixture.Venue.ID),
+	}
+}
+
+// scores lists the periods that have been played, in order
+func (fixture FixtureItem) scores() []fixtureModels.FixtureScore {
+	periods := []struct {
+		name  string
+		score ScorePair
+	}{
+		{"halftime", fixture.Score.Halftime},
+		{"fulltime", fixture.Score.Fulltime},
+		{"extratime", fixture.Score.Extratime},
+		{"penalty", fixture.Score.Penalty},
+	}
+	var scores []fixtureModels.FixtureScore
+	for i, period := range periods {
+		if period.score.Home == nil || period.score.Away == nil {
+			continue
+		}
+		scores = append(scores, fixtureModels.FixtureScore{
+			Period:   period.name,
+			Sequence: i + 1,
+			Home:     *period.score.Home,
+			Away:     *period.score.Away,
+		})
+	}
+	return scores
+}
+
+func (t *TeamData) ToTeam(sportID int) *teamModels.Team {
+	team := &teamModels.Team{
+		Name:        t.Team.Name,
+		SportId:     sportID,
+		Description: "", // API doesn't provide description
+		ImageURL:    t.Team.Logo,
+		IsActive:    true,
+		ApiID:       t.Team.ID,
+	}
+	if t.Venue.ID != 0 {
+		team.Venue = &venueModels.Venue{
+			Name:       t.Venue.Name,
+			Address:    t.Venue.Address,
+			City:       t.Venue.City,
+			Capacity:   t.Venue.Capacity,
+			Surface:    t.Venue.Surface,
+			ImageURL:   t.Venue.Image,
+			Provider:   Name,
+			ExternalID: strconv.Itoa(t.Venue.ID),
+		}
+	}
+	return team
+}
+
+func (tr *TeamsResponse) ToTeams(sportID int) []teamModels.Team {
+	teams := make([]teamModels.Team, 0, len(tr.Response))
+	for _, teamData := range tr.Response {
+		teams = append(teams, *teamData.ToTeam(sportID))
+	}
+	return teams
+}
+
+// teamRef refers to an API-Football team
+func teamRef(sportID, id int, name string) providers.TeamRef {
+	return providers.TeamRef{SportID: sportID, Provider: Name, ExternalID: strconv.Itoa(id), Name: name}
+}
+
+// ToFixture converts an API fixture, looking up both teams. Errors wrapping
+// providers.ErrTeamNotFound, providers.ErrUnknownStatus or a *time.ParseError
+// are problems with this record alone.
+func (fixture FixtureItem) ToFixture(ctx context.Context, teams providers.TeamLookup, sportID int) (fixtureModels.Fixture, error) {
+	teamId1, err := teams.LookupTeam(ctx, teamRef(sportID, fixture.Teams.Home.ID, fixture.Teams.Home.Name))
+	if err != nil {
+		return fixtureModels.Fixture{}, fmt.Errorf("home team %d (%s): %w", fixture.Teams.Home.ID, fixture.Teams.Home.Name, err)
+	}
+	teamId2, err := teams.LookupTeam(ctx, teamRef(sportID, fixture.Teams.Away.ID, fixture.Teams.Away.Name))
+	if err != nil {
+		return fixtureModels.Fixture{}, fmt.Errorf("away team %d (%s): %w", fixture.Teams.Away.ID, fixture.Teams.Away.Name, err)
+	}
+	date, err := time.Parse(time.RFC3339, fixture.Fixture.Date)
+	if err != nil {
+		return fixtureModels.Fixture{}, fmt.Errorf("date: %w", err)
+	}
+	status, err := toStatus(fixture.Fixture.Status.Short)
+	if err != nil {
+		return fixtureModels.Fixture{}, err
+	}
+	return fixtureModels.Fixture{
+		SportID:    sportID,
+		TeamID1:    int(teamId1.ID),
+		TeamID2:    int(teamId2.ID),
+		DateTime:   date,
+		Status:     status,
+		Provider:   Name,
+		ExternalID: strconv.Itoa(fixture.Fixture.ID),
+		HomeScore:  fixture.Goals.Home,
+		AwayScore:  fixture.Goals.Away,
+		Scores:     fixture.scores(),
+		Venue:      fixture.venue(),
+		Details: fixtureModels.Details{
+			HomeTeam:       teamId1.Name,
+			AwayTeam:       teamId2.Name,
+			DateTime:       date,
+			Status:         status,
+			ProviderStatus: fixture.Fixture.Status.Short,
+		},
+	}, nil
+}
+
+// ToFixtures converts every fixture it can, skipping records that reference
+// unknown teams or have an unknown status or unparseable date. Any other error,
+// such as a failed team lookup query, aborts the conversion.
+func (fixturesResp FixturesResponse) ToFixtures(ctx context.Context, teams providers.TeamLookup, sportID int) ([]fixtureModels.Fixture, []providers.Skip, error) {
+	fixtures := make([]fixtureModels.Fixture, 0, len(fixturesResp.Response))
+	var skipped []providers.Skip
+	for _, item := range fixturesResp.Response {
+		fixture, err := item.ToFixture(ctx, teams, sportID)
+		if err != nil {
+			var parseErr *time.ParseError
+			if !errors.Is(err, providers.ErrTeamNotFound) && !errors.Is(err, providers.ErrUnknownStatus) && !errors.As(err, &parseErr) {
+				return nil, nil, fmt.Errorf("fixture %d: %w", item.Fixture.ID, err)
+			}
+			skipped = append(skipped, providers.Skip{
+				ExternalID: strconv.Itoa(item.Fixture.ID),
+				Reason:     err.Error(),
+			})
+			continue
+		}
+		fixtures = append(fixtures, fixture)
+	}
+	return fixtures, skipped, nil
+}
@@ -0,0 +1,42 @@
+// Package fake provides an in-memory providers.Provider for tests
+package fake
+
+import (
+	"context"
+	"mike/pkg/providers"
+	fixtureModels "mike/pkg/routes/fixtures/models"
+	teamModels "mike/pkg/routes/teams/models"
+)
+
+// Provider serves canned teams and fixtures per competition. Err, when set, is
+// returned from every call.
+type Provider struct {
+	ProviderName string
+	Teams        map[providers.Competition][]teamModels.Team
+	Fixtures     map[providers.Competition][]fixtureModels.Fixture
+	Skipped      map[providers.Competition][]providers.Skip
+	Err          error
+}
+
+var _ providers.Provider = (*Provider)(nil)
+
+func (p *Provider) Name() string {
+	if p.ProviderName == "" {
+		return "fake"
+	}
+	return p.ProviderName
+}
+
+func (p *Provider) ListTeams(ctx context.Context, competition providers.Competition) ([]teamModels.Team, error) {
+	if p.Err != nil {
+		return nil, p.Err
+	}
+	return p.Teams[competition], nil
+}
+
+func (p *Provider) ListFixtures(ctx context.Context, competition providers.Competition) ([]fixtureModels.Fixture, []providers.Skip, error) {
+	if p.Err != nil {
+		return nil, nil, p.Err
+	}
+	return p.Fixtures[competition], p.Skipped[competition], nil
+}
@@ -0,0 +1,62 @@
+// Package providers defines the contract for external sources of teams and fixtures
+package providers
+
+import (
+	"context"
+	"errors"
+	fixtureModels "mike/pkg/routes/fixtures/models"
+	teamModels "mike/pkg/routes/teams/models"
+	"time"
+)
+
+// Competition selects one league season at a provider and the sport it belongs to
+type Competition struct {
+	LeagueID int
+	Season   string
+	SportID  int
+	// Name is what the competition is stored as, e.g. "Premier League"
+	Name string
+}
+
+// ErrTeamNotFound is returned by a TeamLookup when no stored team matches
+var ErrTeamNotFound = errors.New("team not found")
+
+// ErrUnknownStatus is returned for a provider status code with no canonical fixture status
+var ErrUnknownStatus = errors.New("unknown fixture status")
+
+// Skip is a provider record that couldn't be converted and was left out of the results
+type Skip struct {
+	ExternalID string `json:"external_id"`
+	Reason     string `json:"reason"`
+}
+
+// Provider fetches teams and fixtures from an external data source. Results are
+// converted to our models but not saved; persistence is left to the caller.
+type Provider interface {
+	// Name identifies the provider, and is stored on the fixtures it returns
+	Name() string
+	ListTeams(ctx context.Context, competition Competition) ([]teamModels.Team, error)
+	// ListFixtures returns the fixtures that could be converted and the records that were skipped
+	ListFixtures(ctx context.Context, competition Competition) ([]fixtureModels.Fixture, []Skip, error)
+}
+
+// WindowLister is implemented by providers that can list only the fixtures of a
+// competition kicking off between from and to, which is cheaper than a full season.
+type WindowLister interface {
+	ListFixturesBetween(ctx context.Context, competition Competition, from, to time.Time) ([]fixtureModels.Fixture, []Skip, error)
+}
+
+// TeamRef is how a provider refers to a team
+type TeamRef struct {
+	SportID  int
+	Provider string
+	// ExternalID is the provider's id for the team, if it has one
+	ExternalID string
+	Name       string
+}
+
+// TeamLookup finds the stored team a provider's team refers to, so fixtures can
+// reference our own team ids. It returns ErrTeamNotFound when there is no match.
+type TeamLookup interface {
+	LookupTeam(ctx context.Context, ref TeamRef) (teamModels.Team, error)
+}
@@ -0,0 +1,102 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	teamModels "mike/pkg/routes/teams/models"
+
+	"github.com/uptrace/bun"
+)
+
+// ErrTeamInReview is returned, wrapped together with ErrTeamNotFound, for a team
+// that only resembles a stored team until someone reviews the match
+var ErrTeamInReview = errors.New("queued for review")
+
+// MinReviewSimilarity is the trigram similarity from which a name that matches
+// no team is queued for review as a possible alias of the closest one
+const MinReviewSimilarity = 0.5
+
+// TeamResolver resolves provider teams against team_aliases and the teams
+// table, for every importer. In order it tries:
+//
+//  1. an alias for the provider's team id
+//  2. a team whose name, or one of whose aliases, matches once normalized
+//  3. the most similar team name, which is queued for review rather than used
+//
+// A match by name is remembered as an alias of the provider's id.
+type TeamResolver struct {
+	DB *bun.DB
+}
+
+var _ TeamLookup = TeamResolver{}
+
+func (r TeamResolver) LookupTeam(ctx context.Context, ref TeamRef) (teamModels.Team, error) {
+	if ref.ExternalID != "" {
+		team, err := teamModels.GetTeamByAlias(ctx, r.DB, ref.SportID, ref.Provider, ref.ExternalID)
+		if err == nil {
+			return team, nil
+		}
+		if err.Error() != "team not found" {
+			return teamModels.Team{}, err
+		}
+	}
+	if ref.Name == "" {
+		return teamModels.Team{}, ErrTeamNotFound
+	}
+
+	teams, err := teamModels.GetTeamsByNormalizedName(ctx, r.DB, ref.SportID, ref.Name)
+	if err != nil {
+		return teamModels.Team{}, err
+	}
+	switch {
+	case len(teams) == 1:
+		if ref.ExternalID != "" {
+			alias := teamModels.TeamAlias{TeamID: teams[0].ID, SportID: ref.SportID, Provider: ref.Provider, ExternalID: ref.ExternalID, Name: ref.Name}
+			if _, err := teamModels.SaveAlias(ctx, r.DB, &alias); err != nil {
+				return teamModels.Team{}, fmt.Errorf("saving team alias: %w", err)
+			}
+		}
+		return teams[0], nil
+	case len(teams) > 1:
+		// Several teams share the name; someone has to pick one
+		return teamModels.Team{}, r.queueReview(ctx, ref, teams[0], 1)
+	}
+
+	review, err := teamModels.GetAliasReviewByName(ctx, r.DB, ref.SportID, ref.Provider, ref.Name)
+	if err == nil {
+		if review.Status == teamModels.ReviewPending {
+			return teamModels.Team{}, fmt.Errorf("%w: %w", ErrTeamNotFound, ErrTeamInReview)
+		}
+		// A rejected match is a team of its own; an approved one would have matched its alias
+		return teamModels.Team{}, ErrTeamNotFound
+	}
+	if err.Error() != "review not found" {
+		return teamModels.Team{}, err
+	}
+
+	candidate, similarity, err := teamModels.FindSimilarTeam(ctx, r.DB, ref.SportID, ref.Provider, ref.Name, MinReviewSimilarity)
+	if err != nil {
+		if err.Error() == "team not found" {
+			return teamModels.Team{}, ErrTeamNotFound
+		}
+		return teamModels.Team{}, err
+	}
+	return teamModels.Team{}, r.queueReview(ctx, ref, candidate, similarity)
+}
+
+// queueReview queues ref as a possible alias of candidate and returns the error reporting it
+func (r TeamResolver) queueReview(ctx context.Context, ref TeamRef, candidate teamModels.Team, similarity float64) error {
+	err := teamModels.QueueAliasReview(ctx, r.DB, teamModels.AliasReview{
+		SportID:         ref.SportID,
+		Provider:        ref.Provider,
+		ExternalID:      ref.ExternalID,
+		Name:            ref.Name,
+		CandidateTeamID: candidate.ID,
+		Similarity:      similarity,
+	})
+	if err != nil {
+		return fmt.Errorf("queueing team alias review: %w", err)
+	}
+	return fmt.Errorf("%w: resembles %s, %w", ErrTeamNotFound, candidate.Name, ErrTeamInReview)
+}
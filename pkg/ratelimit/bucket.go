@@ -0,0 +1,69 @@
+// Package ratelimit limits how many requests each client makes with token
+// buckets, kept in memory or in Postgres
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit is a token bucket: it holds up to Burst requests and refills at
+// PerMinute requests a minute
+type Limit struct {
+	PerMinute int
+	Burst     int
+}
+
+func (l Limit) rate() float64 {
+	return float64(l.PerMinute) / 60
+}
+
+// refillTime is how long an empty bucket takes to fill up; a bucket left
+// alone that long is full, the same as no bucket at all
+func (l Limit) refillTime() time.Duration {
+	return seconds(float64(l.Burst) / l.rate())
+}
+
+// Result is the outcome of taking a token
+type Result struct {
+	Allowed bool
+	// Limit is the bucket's size
+	Limit int
+	// Remaining is how many requests can be made straight away
+	Remaining int
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is how long until the next request is allowed, if this one wasn't
+	RetryAfter time.Duration
+}
+
+// Store keeps the token buckets
+type Store interface {
+	// Take takes a token from the bucket for key, refilled up to now
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// refill returns the tokens a bucket holds at now, having held tokens at updated
+func refill(tokens float64, updated time.Time, limit Limit, now time.Time) float64 {
+	elapsed := max(now.Sub(updated).Seconds(), 0)
+	return min(float64(limit.Burst), tokens+elapsed*limit.rate())
+}
+
+// result describes a bucket left holding tokens after a request that was or wasn't allowed
+func result(tokens float64, allowed bool, limit Limit) Result {
+	res := Result{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((float64(limit.Burst) - tokens) / limit.rate()),
+	}
+	if !allowed {
+		res.RetryAfter = seconds((1 - tokens) / limit.rate())
+	}
+	return res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Max(s, 0) * float64(time.Second))
+}
@@ -0,0 +1,169 @@
+package ratelimit
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"mike/config"
+	"mike/pkg/auth"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/uptrace/bun"
+)
+
+// Route is the limit for routes whose path starts with Prefix
+type Route struct {
+	Prefix string
+	Limit  Limit
+}
+
+// ParseRoutes parses routes written as "prefix=requests_per_minute:burst,..."
+func ParseRoutes(s string) ([]Route, error) {
+	var routes []Route
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		prefix, spec, ok := strings.Cut(entry, "=")
+		perMinute, burst, ok2 := strings.Cut(spec, ":")
+		if !ok || !ok2 || !strings.HasPrefix(prefix, "/") {
+			return nil, fmt.Errorf("invalid rate limit route %q, want prefix=requests_per_minute:burst", entry)
+		}
+		limit := Limit{}
+		var err error
+		if limit.PerMinute, err = strconv.Atoi(perMinute); err != nil {
+			return nil, fmt.Errorf("invalid rate limit route %q: %w", entry, err)
+		}
+		if limit.Burst, err = strconv.Atoi(burst); err != nil {
+			return nil, fmt.Errorf("invalid rate limit route %q: %w", entry, err)
+		}
+		if err := limit.validate(); err != nil {
+			return nil, fmt.Errorf("invalid rate limit route %q: %w", entry, err)
+		}
+		routes = append(routes, Route{Prefix: prefix, Limit: limit})
+	}
+	// Longest prefix first, so the most specific route matches
+	sort.SliceStable(routes, func(i, j int) bool { return len(routes[i].Prefix) > len(routes[j].Prefix) })
+	return routes, nil
+}
+
+func (l Limit) validate() error {
+	if l.PerMinute <= 0 || l.Burst <= 0 {
+		return fmt.Errorf("requests per minute and burst must be positive")
+	}
+	return nil
+}
+
+// Limiter limits each IP to one bucket before authentication, so requests
+// with made-up keys are limited too, and then each API key to a bucket per route
+type Limiter struct {
+	store   Store
+	limit   Limit
+	ipLimit Limit
+	routes  []Route
+	now     func() time.Time
+}
+
+func NewLimiter(store Store, limit, ipLimit Limit, routes []Route) (*Limiter, error) {
+	if err := limit.validate(); err != nil {
+		return nil, err
+	}
+	if err := ipLimit.validate(); err != nil {
+		return nil, fmt.Errorf("invalid IP limit: %w", err)
+	}
+	return &Limiter{store: store, limit: limit, ipLimit: ipLimit, routes: routes, now: time.Now}, nil
+}
+
+// FromConfig builds the limiter cfg describes
+func FromConfig(cfg *config.RateLimitConfig, db *bun.DB) (*Limiter, error) {
+	routes, err := ParseRoutes(cfg.Routes)
+	if err != nil {
+		return nil, err
+	}
+	var store Store
+	switch cfg.Store {
+	case "memory":
+		store = NewMemoryStore()
+	case "postgres":
+		store = NewPostgresStore(db)
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q, must be memory or postgres", cfg.Store)
+	}
+	limit := Limit{PerMinute: cfg.RequestsPerMinute, Burst: cfg.Burst}
+	ipLimit := Limit{PerMinute: cfg.IPRequestsPerMinute, Burst: cfg.IPBurst}
+	return NewLimiter(store, limit, ipLimit, routes)
+}
+
+// route returns the route a path is limited by and the name of its bucket
+func (l *Limiter) route(path string) (string, Limit) {
+	for _, route := range l.routes {
+		if strings.HasPrefix(path, route.Prefix) {
+			return route.Prefix, route.Limit
+		}
+	}
+	return "*", l.limit
+}
+
+// ByIP takes a token from the client IP's bucket for every request but those
+// to the public routes. It goes before authentication, so every request is
+// limited whatever key it claims to have.
+func (l *Limiter) ByIP(public ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			for _, path := range public {
+				if c.Path() == path {
+					return next(c)
+				}
+			}
+			return l.take(c, next, "ip "+c.RealIP(), l.ipLimit)
+		}
+	}
+}
+
+// ByKey takes a token from the bucket for the request's route and API key. It
+// goes after authentication, so only keys that exist get a bucket; requests
+// without one, to the public routes, are let through.
+func (l *Limiter) ByKey() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			apiKey, ok := auth.Key(c)
+			if !ok {
+				return next(c)
+			}
+			name, limit := l.route(c.Path())
+			return l.take(c, next, name+" key:"+strconv.Itoa(apiKey.ID), limit)
+		}
+	}
+}
+
+// take takes a token from bucket, answering 429 once it's empty.
+// RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset describe the
+// bucket, and Retry-After says when to try again. If the store fails the
+// request is let through.
+func (l *Limiter) take(c echo.Context, next echo.HandlerFunc, bucket string, limit Limit) error {
+	res, err := l.store.Take(c.Request().Context(), bucket, limit, l.now())
+	if err != nil {
+		log.Printf("Rate limit unavailable, letting request through: %v", err)
+		return next(c)
+	}
+
+	header := c.Response().Header()
+	header.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+	header.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+	if !res.Allowed {
+		header.Set("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+		return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "Rate limit exceeded"})
+	}
+	return next(c)
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// takeQuery refills the bucket and takes a token in one statement, so
+// replicas taking from the same bucket at once don't both get the last token
+const takeQuery = `
+INSERT INTO rate_limit_buckets AS bucket (key, tokens, allowed, updated_at, refill_seconds)
+VALUES (?0, ?1 - 1, true, ?3, ?4)
+ON CONFLICT (key) DO UPDATE SET
+    allowed = ` + refilled + ` >= 1,
+    tokens = ` + refilled + ` - CASE WHEN ` + refilled + ` >= 1 THEN 1 ELSE 0 END,
+    updated_at = ?3,
+    refill_seconds = ?4
+RETURNING tokens, allowed`
+
+// refilled is the tokens in the bucket at ?3, refilling at ?2 a second up to ?1
+const refilled = `least(?1, bucket.tokens + greatest(extract(epoch FROM ?3::timestamptz - bucket.updated_at)::double precision, 0) * ?2)`
+
+// PostgresStore keeps buckets in the rate_limit_buckets table, so every
+// replica shares each client's limit
+type PostgresStore struct {
+	db *bun.DB
+	// lastSweep is the unix time full buckets were last deleted
+	lastSweep atomic.Int64
+}
+
+func NewPostgresStore(db *bun.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	if last := s.lastSweep.Load(); now.Unix()-last >= int64(sweepInterval.Seconds()) && s.lastSweep.CompareAndSwap(last, now.Unix()) {
+		if err := s.sweep(ctx, now); err != nil {
+			return Result{}, err
+		}
+	}
+
+	var tokens float64
+	var allowed bool
+	err := s.db.NewRaw(takeQuery, key, float64(limit.Burst), limit.rate(), now, limit.refillTime().Seconds()).Scan(ctx, &tokens, &allowed)
+	if err != nil {
+		return Result{}, err
+	}
+	return result(tokens, allowed, limit), nil
+}
+
+func (s *PostgresStore) sweep(ctx context.Context, now time.Time) error {
+	_, err := s.db.NewDelete().
+		Table("rate_limit_buckets").
+		Where("updated_at + make_interval(secs => refill_seconds) <= ?", now).
+		Exec(ctx)
+	return err
+}
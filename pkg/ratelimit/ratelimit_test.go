@@ -0,0 +1,194 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"mike/pkg/auth"
+	"mike/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// testStores are the stores every bucket test runs against
+func testStores(t *testing.T) map[string]Store {
+	db := utils.GetDatabase()
+	truncate := func() {
+		_, _ = db.NewRaw("TRUNCATE TABLE rate_limit_buckets").Exec(context.Background())
+	}
+	truncate()
+	t.Cleanup(truncate)
+	return map[string]Store{"memory": NewMemoryStore(), "postgres": NewPostgresStore(db)}
+}
+
+func Test_StoreTake(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			limit := Limit{PerMinute: 60, Burst: 3}
+			start := time.Date(2025, 8, 16, 14, 0, 0, 0, time.UTC)
+
+			for want := 2; want >= 0; want-- {
+				res, err := store.Take(ctx, "client", limit, start)
+				assert.NoError(t, err)
+				assert.True(t, res.Allowed)
+				assert.Equal(t, 3, res.Limit)
+				assert.Equal(t, want, res.Remaining)
+			}
+
+			res, err := store.Take(ctx, "client", limit, start)
+			assert.NoError(t, err)
+			assert.False(t, res.Allowed)
+			assert.Equal(t, 0, res.Remaining)
+			assert.Equal(t, time.Second, res.RetryAfter)
+			assert.Equal(t, 3*time.Second, res.Reset)
+
+			// Other clients have buckets of their own
+			res, err = store.Take(ctx, "other client", limit, start)
+			assert.NoError(t, err)
+			assert.True(t, res.Allowed)
+
+			// A token a second comes back
+			res, err = store.Take(ctx, "client", limit, start.Add(1500*time.Millisecond))
+			assert.NoError(t, err)
+			assert.True(t, res.Allowed)
+			assert.Equal(t, 0, res.Remaining)
+
+			// But never more than the burst
+			res, err = store.Take(ctx, "client", limit, start.Add(time.Hour))
+			assert.NoError(t, err)
+			assert.True(t, res.Allowed)
+			assert.Equal(t, 2, res.Remaining)
+		})
+	}
+}
+
+func Test_ParseRoutes(t *testing.T) {
+	routes, err := ParseRoutes("/v1/fixtures=120:20, /v1/fixtures/daterange=30:5,")
+	assert.NoError(t, err)
+	assert.Equal(t, []Route{
+		{Prefix: "/v1/fixtures/daterange", Limit: Limit{PerMinute: 30, Burst: 5}},
+		{Prefix: "/v1/fixtures", Limit: Limit{PerMinute: 120, Burst: 20}},
+	}, routes)
+
+	routes, err = ParseRoutes("")
+	assert.NoError(t, err)
+	assert.Empty(t, routes)
+
+	for _, invalid := range []string{"/v1/fixtures=120", "v1/fixtures=120:20", "/v1/fixtures=x:20", "/v1/fixtures=120:0"} {
+		_, err := ParseRoutes(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+// testServer serves ok behind limiter, authenticating the keys in keys by ID
+func testServer(limiter *Limiter, keys map[string]int) *echo.Echo {
+	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+	e.Use(limiter.ByIP("/health"))
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Path() == "/health" {
+				return next(c)
+			}
+			id, ok := keys[auth.KeyFromRequest(c.Request())]
+			if !ok {
+				return c.NoContent(http.StatusUnauthorized)
+			}
+			auth.SetKey(c, auth.APIKey{ID: id})
+			return next(c)
+		}
+	})
+	e.Use(limiter.ByKey())
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	e.GET("/health", ok)
+	e.GET("/v1/fixtures", ok)
+	e.POST("/v1/fixtures/daterange", ok)
+	return e
+}
+
+func request(e *echo.Echo, method, path, key, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = ip + ":1234"
+	if key != "" {
+		req.Header.Set("X-API-Key", key)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func Test_Middleware(t *testing.T) {
+	routes, err := ParseRoutes("/v1/fixtures/daterange=60:1")
+	assert.NoError(t, err)
+	limiter, err := NewLimiter(NewMemoryStore(), Limit{PerMinute: 60, Burst: 2}, Limit{PerMinute: 600, Burst: 100}, routes)
+	assert.NoError(t, err)
+	now := time.Date(2025, 8, 16, 14, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+	e := testServer(limiter, map[string]int{"key-a": 1, "key-b": 2})
+
+	rec := request(e, http.MethodGet, "/v1/fixtures", "key-a", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Reset"))
+
+	// The same key from another IP shares the bucket
+	rec = request(e, http.MethodGet, "/v1/fixtures", "key-a", "10.0.0.2")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = request(e, http.MethodGet, "/v1/fixtures", "key-a", "10.0.0.3")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+
+	// Other keys from the same IP have buckets of their own
+	rec = request(e, http.MethodGet, "/v1/fixtures", "key-b", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Routes with a limit of their own have a bucket of their own
+	rec = request(e, http.MethodPost, "/v1/fixtures/daterange", "key-a", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Limit"))
+	rec = request(e, http.MethodPost, "/v1/fixtures/daterange", "key-a", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+	// Public routes aren't limited
+	for range 5 {
+		rec = request(e, http.MethodGet, "/health", "key-a", "10.0.0.1")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("RateLimit-Limit"))
+	}
+
+	now = now.Add(time.Second)
+	rec = request(e, http.MethodGet, "/v1/fixtures", "key-a", "10.0.0.3")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func Test_MiddlewareLimitsMadeUpKeysByIP(t *testing.T) {
+	limiter, err := NewLimiter(NewMemoryStore(), Limit{PerMinute: 60, Burst: 2}, Limit{PerMinute: 60, Burst: 5}, nil)
+	assert.NoError(t, err)
+	now := time.Date(2025, 8, 16, 14, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+	e := testServer(limiter, map[string]int{"key-a": 1})
+
+	// A new key every request still drains the IP's bucket
+	for i := range 5 {
+		rec := request(e, http.MethodGet, "/v1/fixtures", fmt.Sprintf("made-up-%d", i), "10.0.0.1")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	}
+	rec := request(e, http.MethodGet, "/v1/fixtures", "made-up-5", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	// Even a real key can't get past an empty IP bucket
+	rec = request(e, http.MethodGet, "/v1/fixtures", "key-a", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+	// Other IPs aren't affected
+	rec = request(e, http.MethodGet, "/v1/fixtures", "key-a", "10.0.0.2")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,96 @@
+package admin
+
+import (
+	"mike/pkg/application"
+	"mike/pkg/auth"
+	"mike/pkg/pagination"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// APIKeyRequest is the body of CreateAPIKey
+type APIKeyRequest struct {
+	Owner     string     `json:"owner"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// CreatedAPIKey is a newly issued key, the only time the key itself is shown
+type CreatedAPIKey struct {
+	auth.APIKey
+	Key string `json:"key"`
+}
+
+// CreateAPIKey issues a key to an owner with the given scopes
+func CreateAPIKey(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	var req APIKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	req.Owner = strings.TrimSpace(req.Owner)
+	if req.Owner == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "owner is required"})
+	}
+	if len(req.Scopes) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "scopes is required"})
+	}
+	for _, scope := range req.Scopes {
+		if !slices.Contains(auth.Scopes, scope) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Unknown scope " + scope})
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "expires_at must be in the future"})
+	}
+
+	apiKey := auth.APIKey{
+		Owner:     req.Owner,
+		Scopes:    slices.Compact(slices.Sorted(slices.Values(req.Scopes))),
+		ExpiresAt: req.ExpiresAt,
+	}
+	key, err := auth.CreateKey(app.DB, &apiKey)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create API key"})
+	}
+	return c.JSON(http.StatusCreated, CreatedAPIKey{APIKey: apiKey, Key: key})
+}
+
+// GetAPIKeys lists issued keys, oldest first, revoked ones included
+func GetAPIKeys(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	page, err := pagination.ParseParams(c.QueryParam("limit"), c.QueryParam("cursor"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": pagination.ErrorMessage(err)})
+	}
+	keys, err := auth.ListKeys(app.DB, page)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get API keys"})
+	}
+	return c.JSON(http.StatusOK, keys)
+}
+
+// RevokeAPIKey stops a key from authenticating
+func RevokeAPIKey(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid API key ID"})
+	}
+	if apiKey, ok := auth.Key(c); ok && apiKey.ID == id {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Can't revoke the key making the request"})
+	}
+	apiKey, err := auth.RevokeKey(app.DB, id)
+	if err != nil {
+		if err.Error() == "api key not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "API key not found or already revoked"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to revoke API key"})
+	}
+	return c.JSON(http.StatusOK, apiKey)
+}
@@ -0,0 +1,250 @@
+package admin
+
+import (
+	"encoding/json"
+	"mike/pkg/application"
+	competitionModels "mike/pkg/routes/competitions/models"
+	fixtureModels "mike/pkg/routes/fixtures/models"
+	teamModels "mike/pkg/routes/teams/models"
+	venueModels "mike/pkg/routes/venues/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// optional is a request field that tells a field set to null apart from one
+// left out
+type optional[T any] struct {
+	Set   bool
+	Value *T
+}
+
+func (o *optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	return json.Unmarshal(data, &o.Value)
+}
+
+// FixtureRequest is the body of CreateFixture and UpdateFixture; fields left
+// out of an update keep their value, a season_id or venue_id of 0 clears it,
+// and so does a null score. scores replaces the period scores; when it's left
+// out, changing the final score clears them, as they'd no longer add up.
+type FixtureRequest struct {
+	SportID   *int                                   `json:"sport_id"`
+	TeamID1   *int                                   `json:"team_id_1"`
+	TeamID2   *int                                   `json:"team_id_2"`
+	DateTime  *time.Time                             `json:"date_time"`
+	Status    *fixtureModels.Status                  `json:"status"`
+	SeasonID  *int                                   `json:"season_id"`
+	VenueID   *int                                   `json:"venue_id"`
+	HomeScore optional[int]                          `json:"home_score"`
+	AwayScore optional[int]                          `json:"away_score"`
+	Scores    optional[[]fixtureModels.FixtureScore] `json:"scores"`
+}
+
+// apply copies the fields set in req onto fixture
+func (req FixtureRequest) apply(fixture *fixtureModels.Fixture) {
+	if req.SportID != nil {
+		fixture.SportID = *req.SportID
+	}
+	if req.TeamID1 != nil {
+		fixture.TeamID1 = *req.TeamID1
+	}
+	if req.TeamID2 != nil {
+		fixture.TeamID2 = *req.TeamID2
+	}
+	if req.DateTime != nil {
+		fixture.DateTime = req.DateTime.UTC()
+	}
+	if req.Status != nil {
+		fixture.Status = *req.Status
+	}
+	if req.SeasonID != nil {
+		fixture.SeasonID = *req.SeasonID
+	}
+	if req.VenueID != nil {
+		fixture.VenueID = *req.VenueID
+	}
+	homeScore, awayScore := fixture.HomeScore, fixture.AwayScore
+	if req.HomeScore.Set {
+		fixture.HomeScore = req.HomeScore.Value
+	}
+	if req.AwayScore.Set {
+		fixture.AwayScore = req.AwayScore.Value
+	}
+	if req.Scores.Set {
+		fixture.Scores = nil
+		if req.Scores.Value != nil {
+			fixture.Scores = *req.Scores.Value
+		}
+	} else if !sameScore(homeScore, fixture.HomeScore) || !sameScore(awayScore, fixture.AwayScore) {
+		fixture.Scores = nil
+	}
+}
+
+// sameScore reports whether two scores, either of which may be unknown, are equal
+func sameScore(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// validateFixture checks that a fixture is between two different teams of its
+// sport, with a valid status and scores and an existing season and venue,
+// returning the error message for a bad request. It fills in the fixture's
+// details from its teams.
+func validateFixture(app *application.App, fixture *fixtureModels.Fixture) (string, error) {
+	if fixture.SportID <= 0 || fixture.TeamID1 <= 0 || fixture.TeamID2 <= 0 || fixture.DateTime.IsZero() {
+		return "sport_id, team_id_1, team_id_2 and date_time are required", nil
+	}
+	if fixture.TeamID1 == fixture.TeamID2 {
+		return "Teams must be different", nil
+	}
+	if !fixture.Status.Valid() {
+		return "Invalid status", nil
+	}
+	if (fixture.HomeScore == nil) != (fixture.AwayScore == nil) {
+		return "home_score and away_score must be given together", nil
+	}
+	if fixture.HomeScore != nil && (*fixture.HomeScore < 0 || *fixture.AwayScore < 0) {
+		return "Scores can't be negative", nil
+	}
+	periods := make(map[string]bool, len(fixture.Scores))
+	for _, score := range fixture.Scores {
+		if score.Period == "" {
+			return "Every score needs a period", nil
+		}
+		if periods[score.Period] {
+			return "Each period can only be scored once", nil
+		}
+		periods[score.Period] = true
+		if score.Home < 0 || score.Away < 0 {
+			return "Scores can't be negative", nil
+		}
+	}
+
+	teams, err := teamModels.GetTeamsByIDs(app.DB, []int{fixture.TeamID1, fixture.TeamID2})
+	if err != nil {
+		return "", err
+	}
+	home, ok := teams[fixture.TeamID1]
+	if !ok {
+		return "Invalid team_id_1", nil
+	}
+	away, ok := teams[fixture.TeamID2]
+	if !ok {
+		return "Invalid team_id_2", nil
+	}
+	if home.SportId != fixture.SportID || away.SportId != fixture.SportID {
+		return "Teams must belong to the fixture's sport", nil
+	}
+	if fixture.SeasonID != 0 {
+		if _, err := competitionModels.GetSeasonDetails(app.DB, fixture.SeasonID); err != nil {
+			if err.Error() == "season not found" {
+				return "Invalid season_id", nil
+			}
+			return "", err
+		}
+	}
+	if fixture.VenueID != 0 {
+		if _, err := venueModels.GetVenueDetails(app.DB, fixture.VenueID); err != nil {
+			if err.Error() == "venue not found" {
+				return "Invalid venue_id", nil
+			}
+			return "", err
+		}
+	}
+
+	fixture.Details.HomeTeam = home.Name
+	fixture.Details.AwayTeam = away.Name
+	fixture.Details.DateTime = fixture.DateTime
+	fixture.Details.Status = fixture.Status
+	return "", nil
+}
+
+func CreateFixture(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	var req FixtureRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	fixture := fixtureModels.Fixture{Status: fixtureModels.StatusScheduled}
+	req.apply(&fixture)
+	message, err := validateFixture(app, &fixture)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to validate fixture"})
+	}
+	if message != "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": message})
+	}
+
+	if err := fixtureModels.CreateFixture(app.DB, &fixture); err != nil {
+		if err.Error() == "fixture already exists" {
+			return c.JSON(http.StatusConflict, map[string]string{"error": "These teams already play at that time"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create fixture"})
+	}
+	return c.JSON(http.StatusCreated, fixture)
+}
+
+// UpdateFixture corrects a fixture. The next sync of a provider fixture
+// overwrites whatever its provider reports differently.
+func UpdateFixture(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	fixtureID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid fixture ID"})
+	}
+	var req FixtureRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	fixture, err := fixtureModels.GetFixture(app.DB, fixtureID)
+	if err != nil {
+		if err.Error() == "fixture not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Fixture not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get fixture"})
+	}
+	req.apply(&fixture)
+	message, err := validateFixture(app, &fixture)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to validate fixture"})
+	}
+	if message != "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": message})
+	}
+
+	if _, err := fixtureModels.UpdateFixture(app.DB, fixture); err != nil {
+		switch err.Error() {
+		case "fixture not found":
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Fixture not found"})
+		case "fixture already exists":
+			return c.JSON(http.StatusConflict, map[string]string{"error": "These teams already play at that time"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update fixture"})
+	}
+	fixture, err = fixtureModels.GetFixture(app.DB, fixtureID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get fixture"})
+	}
+	return c.JSON(http.StatusOK, fixture)
+}
+
+// DeleteFixture soft deletes a fixture
+func DeleteFixture(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	fixtureID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid fixture ID"})
+	}
+	if err := fixtureModels.DeleteFixture(app.DB, fixtureID); err != nil {
+		if err.Error() == "fixture not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Fixture not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete fixture"})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,178 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	fixtureModels "mike/pkg/routes/fixtures/models"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const fixtureTime = "2025-08-16T14:00:00Z"
+
+// createFixture creates a fixture through the API and returns it
+func createFixture(t *testing.T, e *echo.Echo, sportID, homeID, awayID int) fixtureModels.Fixture {
+	body := fmt.Sprintf(`{"sport_id": %d, "team_id_1": %d, "team_id_2": %d, "date_time": %q}`, sportID, homeID, awayID, fixtureTime)
+	rec := request(e, http.MethodPost, "/v1/admin/fixtures", body)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var fixture fixtureModels.Fixture
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &fixture))
+	return fixture
+}
+
+func Test_CreateFixture(t *testing.T) {
+	app, e := setupTestApp(t)
+	truncateTables(app.DB)
+	defer truncateTables(app.DB)
+
+	sport := createSport(t, e, "Test Sport Admin")
+	other := createSport(t, e, "Other Sport Admin")
+	home := createTeam(t, e, sport.ID, "Home Admin")
+	away := createTeam(t, e, sport.ID, "Away Admin")
+	outsider := createTeam(t, e, other.ID, "Outsider Admin")
+
+	fixture := createFixture(t, e, sport.ID, home.ID, away.ID)
+	assert.NotZero(t, fixture.ID)
+	assert.Equal(t, fixtureModels.StatusScheduled, fixture.Status)
+	assert.Equal(t, "Home Admin", fixture.Details.HomeTeam)
+	assert.Equal(t, "Away Admin", fixture.Details.AwayTeam)
+
+	fields := func(extra string) string {
+		return fmt.Sprintf(`{"sport_id": %d, "team_id_1": %d, "team_id_2": %d, "date_time": "2025-08-23T14:00:00Z"%s}`, sport.ID, home.ID, away.ID, extra)
+	}
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "invalid body", body: `{"sport_id": `, expectedStatus: http.StatusBadRequest},
+		{name: "missing fields", body: fmt.Sprintf(`{"sport_id": %d}`, sport.ID), expectedStatus: http.StatusBadRequest},
+		{name: "same team twice", body: fmt.Sprintf(`{"sport_id": %d, "team_id_1": %d, "team_id_2": %d, "date_time": %q}`, sport.ID, home.ID, home.ID, fixtureTime), expectedStatus: http.StatusBadRequest},
+		{name: "invalid status", body: fields(`, "status": "paused"`), expectedStatus: http.StatusBadRequest},
+		{name: "half a score", body: fields(`, "home_score": 1`), expectedStatus: http.StatusBadRequest},
+		{name: "negative score", body: fields(`, "home_score": -1, "away_score": 0`), expectedStatus: http.StatusBadRequest},
+		{name: "unknown team", body: fmt.Sprintf(`{"sport_id": %d, "team_id_1": %d, "team_id_2": 999999, "date_time": %q}`, sport.ID, home.ID, fixtureTime), expectedStatus: http.StatusBadRequest},
+		{name: "team of another sport", body: fmt.Sprintf(`{"sport_id": %d, "team_id_1": %d, "team_id_2": %d, "date_time": %q}`, sport.ID, home.ID, outsider.ID, fixtureTime), expectedStatus: http.StatusBadRequest},
+		{name: "unknown venue", body: fields(`, "venue_id": 999999`), expectedStatus: http.StatusBadRequest},
+		{name: "already exists", body: fmt.Sprintf(`{"sport_id": %d, "team_id_1": %d, "team_id_2": %d, "date_time": %q}`, sport.ID, home.ID, away.ID, fixtureTime), expectedStatus: http.StatusConflict},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := request(e, http.MethodPost, "/v1/admin/fixtures", test.body)
+			assert.Equal(t, test.expectedStatus, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func Test_UpdateFixture(t *testing.T) {
+	app, e := setupTestApp(t)
+	truncateTables(app.DB)
+	defer truncateTables(app.DB)
+
+	sport := createSport(t, e, "Test Sport Admin")
+	home := createTeam(t, e, sport.ID, "Home Admin")
+	away := createTeam(t, e, sport.ID, "Away Admin")
+	fixture := createFixture(t, e, sport.ID, home.ID, away.ID)
+	path := "/v1/admin/fixtures/" + strconv.Itoa(fixture.ID)
+
+	rec := request(e, http.MethodPatch, path, `{"status": "finished", "home_score": 2, "away_score": 1}`)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var updated fixtureModels.Fixture
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+	assert.Equal(t, fixtureModels.StatusFinished, updated.Status)
+	if assert.NotNil(t, updated.HomeScore) && assert.NotNil(t, updated.AwayScore) {
+		assert.Equal(t, 2, *updated.HomeScore)
+		assert.Equal(t, 1, *updated.AwayScore)
+	}
+	assert.Equal(t, home.ID, updated.TeamID1)
+
+	// Period scores are replaced along with the final score
+	rec = request(e, http.MethodPatch, path, `{"scores": [{"period": "first_half", "sequence": 1, "home": 1, "away": 1}, {"period": "second_half", "sequence": 2, "home": 1, "away": 0}]}`)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	updated = fixtureModels.Fixture{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+	if assert.Len(t, updated.Scores, 2) {
+		assert.Equal(t, "first_half", updated.Scores[0].Period)
+		assert.Equal(t, 1, updated.Scores[1].Home)
+	}
+
+	// Changing the final score drops period scores that no longer add up
+	rec = request(e, http.MethodPatch, path, `{"home_score": 3, "away_score": 1}`)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	updated = fixtureModels.Fixture{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+	assert.Empty(t, updated.Scores)
+
+	// A null score clears it
+	rec = request(e, http.MethodPatch, path, `{"status": "scheduled", "home_score": null, "away_score": null}`)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	updated = fixtureModels.Fixture{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+	assert.Nil(t, updated.HomeScore)
+	assert.Nil(t, updated.AwayScore)
+
+	// A second fixture between the same teams a week later
+	later := fmt.Sprintf(`{"sport_id": %d, "team_id_1": %d, "team_id_2": %d, "date_time": "2025-08-23T14:00:00Z"}`, sport.ID, home.ID, away.ID)
+	rec = request(e, http.MethodPost, "/v1/admin/fixtures", later)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "invalid id", path: "/v1/admin/fixtures/abc", body: `{}`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid body", path: path, body: `{"status": 1}`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid status", path: path, body: `{"status": "paused"}`, expectedStatus: http.StatusBadRequest},
+		{name: "same team twice", path: path, body: fmt.Sprintf(`{"team_id_2": %d}`, home.ID), expectedStatus: http.StatusBadRequest},
+		{name: "half a score cleared", path: path, body: `{"home_score": 1, "away_score": null}`, expectedStatus: http.StatusBadRequest},
+		{name: "score without a period", path: path, body: `{"scores": [{"home": 1, "away": 0}]}`, expectedStatus: http.StatusBadRequest},
+		{name: "period scored twice", path: path, body: `{"scores": [{"period": "first_half", "home": 1, "away": 0}, {"period": "first_half", "home": 0, "away": 0}]}`, expectedStatus: http.StatusBadRequest},
+		{name: "missing fixture", path: "/v1/admin/fixtures/999999", body: `{}`, expectedStatus: http.StatusNotFound},
+		{name: "clashes with another fixture", path: path, body: `{"date_time": "2025-08-23T14:00:00Z"}`, expectedStatus: http.StatusConflict},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := request(e, http.MethodPatch, test.path, test.body)
+			assert.Equal(t, test.expectedStatus, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func Test_DeleteFixture(t *testing.T) {
+	app, e := setupTestApp(t)
+	truncateTables(app.DB)
+	defer truncateTables(app.DB)
+
+	sport := createSport(t, e, "Test Sport Admin")
+	home := createTeam(t, e, sport.ID, "Home Admin")
+	away := createTeam(t, e, sport.ID, "Away Admin")
+	fixture := createFixture(t, e, sport.ID, home.ID, away.ID)
+	path := "/v1/admin/fixtures/" + strconv.Itoa(fixture.ID)
+
+	rec := request(e, http.MethodDelete, path, "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	// Deleted fixtures are gone from searches
+	rec = request(e, http.MethodGet, fmt.Sprintf("/v1/fixtures?sport_id=%d", sport.ID), "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var search struct {
+		Data  []fixtureModels.Fixture `json:"data"`
+		Total int                     `json:"total"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &search))
+	assert.Empty(t, search.Data)
+	assert.Zero(t, search.Total)
+
+	rec = request(e, http.MethodDelete, path, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code, "deleting twice")
+	rec = request(e, http.MethodPatch, path, `{"status": "cancelled"}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code, "updating a deleted fixture")
+	rec = request(e, http.MethodDelete, "/v1/admin/fixtures/abc", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,194 @@
+package admin
+
+import (
+	"mike/pkg/application"
+	"mike/pkg/ingest"
+	"mike/pkg/pagination"
+	teamModels "mike/pkg/routes/teams/models"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+var runStatuses = map[string]bool{
+	ingest.RunRunning:   true,
+	ingest.RunSucceeded: true,
+	ingest.RunFailed:    true,
+}
+
+// GetSyncRuns lists ingestion runs, newest first, optionally filtered by
+// provider, league_id, season and status
+func GetSyncRuns(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+
+	filter := ingest.SyncRunFilter{
+		Provider: c.QueryParam("provider"),
+		Season:   c.QueryParam("season"),
+		Status:   c.QueryParam("status"),
+	}
+	if raw := c.QueryParam("league_id"); raw != "" {
+		leagueID, err := strconv.Atoi(raw)
+		if err != nil || leagueID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid league_id"})
+		}
+		filter.LeagueID = leagueID
+	}
+	if filter.Status != "" && !runStatuses[filter.Status] {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid status, must be running, succeeded or failed"})
+	}
+	page, err := pagination.ParseParams(c.QueryParam("limit"), c.QueryParam("cursor"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": pagination.ErrorMessage(err)})
+	}
+
+	runs, err := ingest.ListSyncRuns(app.DB, filter, page)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get sync runs"})
+	}
+	return c.JSON(http.StatusOK, runs)
+}
+
+var reviewStatuses = map[string]bool{
+	teamModels.ReviewPending:  true,
+	teamModels.ReviewApproved: true,
+	teamModels.ReviewRejected: true,
+}
+
+// TeamAliasRequest is the body of CreateTeamAlias
+type TeamAliasRequest struct {
+	TeamID     int    `json:"team_id"`
+	Provider   string `json:"provider"`
+	ExternalID string `json:"external_id"`
+	Name       string `json:"name"`
+}
+
+// CreateTeamAlias records another name, or a provider's id, for a team, e.g.
+// "Man Utd" for Manchester United
+func CreateTeamAlias(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	var req TeamAliasRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	req.Provider = strings.TrimSpace(req.Provider)
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Provider == "" || req.Name == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "provider and name are required"})
+	}
+
+	team, err := teamModels.GetTeamDetails(app.DB, req.TeamID)
+	if err != nil {
+		if err.Error() == "team not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Team not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get team details"})
+	}
+
+	alias := teamModels.TeamAlias{
+		TeamID:     team.ID,
+		SportID:    team.SportId,
+		Provider:   req.Provider,
+		ExternalID: strings.TrimSpace(req.ExternalID),
+		Name:       req.Name,
+	}
+	created, err := teamModels.SaveAlias(c.Request().Context(), app.DB, &alias)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save alias"})
+	}
+	if !created {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "Alias already exists"})
+	}
+	return c.JSON(http.StatusCreated, alias)
+}
+
+// GetTeamAliasReviews lists provider teams that resembled a stored team,
+// oldest first, optionally filtered by status
+func GetTeamAliasReviews(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	status := c.QueryParam("status")
+	if status != "" && !reviewStatuses[status] {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid status, must be pending, approved or rejected"})
+	}
+	page, err := pagination.ParseParams(c.QueryParam("limit"), c.QueryParam("cursor"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": pagination.ErrorMessage(err)})
+	}
+	reviews, err := teamModels.ListAliasReviews(app.DB, status, page)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get alias reviews"})
+	}
+	return c.JSON(http.StatusOK, reviews)
+}
+
+// ApproveTeamAliasReview makes a reviewed name an alias of the team in the
+// body's team_id, or of the suggested candidate when there is none
+func ApproveTeamAliasReview(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	reviewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid review ID"})
+	}
+	var req struct {
+		TeamID int `json:"team_id"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	review, err := teamModels.GetAliasReview(app.DB, reviewID)
+	if err != nil {
+		if err.Error() == "review not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Review not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get alias review"})
+	}
+	teamID := req.TeamID
+	if teamID == 0 {
+		teamID = review.CandidateTeamID
+	}
+	team, err := teamModels.GetTeamDetails(app.DB, teamID)
+	if err != nil {
+		if err.Error() == "team not found" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid team_id"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get team details"})
+	}
+	if team.SportId != review.SportID {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Team is in a different sport"})
+	}
+
+	review, err = teamModels.ApproveAliasReview(app.DB, reviewID, team.ID)
+	if err != nil {
+		if err.Error() == "review not found" {
+			return c.JSON(http.StatusConflict, map[string]string{"error": "Review already resolved"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to approve alias review"})
+	}
+	return c.JSON(http.StatusOK, review)
+}
+
+// RejectTeamAliasReview marks a reviewed name as a team of its own, to be
+// added by the next sync of its provider's teams
+func RejectTeamAliasReview(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	reviewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid review ID"})
+	}
+	if _, err := teamModels.GetAliasReview(app.DB, reviewID); err != nil {
+		if err.Error() == "review not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Review not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get alias review"})
+	}
+	review, err := teamModels.RejectAliasReview(app.DB, reviewID)
+	if err != nil {
+		if err.Error() == "review not found" {
+			return c.JSON(http.StatusConflict, map[string]string{"error": "Review already resolved"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to reject alias review"})
+	}
+	return c.JSON(http.StatusOK, review)
+}
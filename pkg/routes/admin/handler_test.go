@@ -0,0 +1,171 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"mike/config"
+	"mike/pkg/application"
+	"mike/pkg/auth"
+	"mike/pkg/ingest"
+	"mike/pkg/pagination"
+	"mike/pkg/routes/fixtures"
+	"mike/pkg/routes/sports"
+	"mike/pkg/routes/teams"
+	"mike/utils"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+// setupTestApp serves the admin routes, and the routes that read what they
+// write, to requests authenticated with an admin key
+func setupTestApp(t *testing.T) (*application.App, *echo.Echo) {
+	cfg := config.GetConfig()
+	app, err := application.New(cfg)
+	assert.NoError(t, err, "Failed to create application")
+	app.DB = utils.GetDatabase()
+
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("app", app)
+			auth.SetKey(c, auth.APIKey{Scopes: []string{auth.ScopeAdmin}})
+			return next(c)
+		}
+	})
+	RegisterRoutes(e, app)
+	sports.RegisterRoutes(e, app)
+	teams.RegisterRoutes(e, app)
+	fixtures.RegisterRoutes(e, app)
+	return app, e
+}
+
+// truncateTables empties the tables the admin tests write to
+func truncateTables(db *bun.DB) {
+	for _, table := range []string{"fixtures", "teams", "sports", "venues", "sync_runs", "ingestion_skips"} {
+		_, _ = db.NewRaw("TRUNCATE TABLE " + table + " CASCADE").Exec(context.Background())
+	}
+}
+
+// request sends a request with a JSON body, if body isn't empty, and returns the response
+func request(e *echo.Echo, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func Test_AdminRoutesNeedAdminScope(t *testing.T) {
+	e := echo.New()
+	RegisterRoutes(e, nil)
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			auth.SetKey(c, auth.APIKey{Scopes: []string{auth.ScopeReadSports, auth.ScopeReadTeams, auth.ScopeReadFixtures}})
+			return next(c)
+		}
+	})
+	for _, path := range []string{"/v1/admin/sports", "/v1/admin/teams", "/v1/admin/fixtures"} {
+		rec := request(e, http.MethodPost, path, `{}`)
+		assert.Equal(t, http.StatusForbidden, rec.Code, path)
+	}
+}
+
+func Test_GetSyncRuns(t *testing.T) {
+	app, e := setupTestApp(t)
+	truncateTables(app.DB)
+	defer truncateTables(app.DB)
+
+	started := time.Date(2025, 8, 16, 12, 0, 0, 0, time.UTC)
+	message := "provider unavailable"
+	runs := []ingest.SyncRun{
+		{Provider: "api-football", Kind: "fixtures", LeagueID: 39, Season: "2025", Status: ingest.RunSucceeded, Inserted: 10},
+		{Provider: "api-football", Kind: "fixtures", LeagueID: 140, Season: "2025", Status: ingest.RunFailed, Error: &message},
+		{Provider: "api-football", Kind: "fixtures", LeagueID: 39, Season: "2024", Status: ingest.RunSucceeded, Updated: 3},
+		{Provider: "fake", Kind: "teams", LeagueID: 39, Season: "2025", Status: ingest.RunRunning},
+	}
+	for i := range runs {
+		runs[i].StartedAt = started.Add(time.Duration(i) * time.Minute)
+		_, err := app.DB.NewInsert().Model(&runs[i]).Returning("id").Exec(context.Background())
+		assert.NoError(t, err)
+	}
+
+	list := func(t *testing.T, query url.Values) pagination.Page[ingest.SyncRun] {
+		rec := request(e, http.MethodGet, "/v1/admin/sync-runs?"+query.Encode(), "")
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var page pagination.Page[ingest.SyncRun]
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		return page
+	}
+	ids := func(page pagination.Page[ingest.SyncRun]) []int {
+		ids := []int{}
+		for _, run := range page.Data {
+			ids = append(ids, run.ID)
+		}
+		return ids
+	}
+
+	t.Run("newest first", func(t *testing.T) {
+		page := list(t, url.Values{})
+		assert.Equal(t, []int{runs[3].ID, runs[2].ID, runs[1].ID, runs[0].ID}, ids(page))
+		assert.Empty(t, page.NextCursor)
+		if assert.NotEmpty(t, page.Data) {
+			assert.Equal(t, "fake", page.Data[0].Provider)
+			assert.Equal(t, ingest.RunRunning, page.Data[0].Status)
+		}
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		page := list(t, url.Values{"provider": {"api-football"}, "league_id": {"39"}})
+		assert.Equal(t, []int{runs[2].ID, runs[0].ID}, ids(page))
+
+		page = list(t, url.Values{"season": {"2025"}, "status": {"failed"}})
+		if assert.Equal(t, []int{runs[1].ID}, ids(page)) {
+			assert.Equal(t, &message, page.Data[0].Error)
+		}
+	})
+
+	t.Run("paged", func(t *testing.T) {
+		first := list(t, url.Values{"limit": {"3"}})
+		assert.Equal(t, []int{runs[3].ID, runs[2].ID, runs[1].ID}, ids(first))
+		assert.NotEmpty(t, first.NextCursor)
+
+		second := list(t, url.Values{"limit": {"3"}, "cursor": {first.NextCursor}})
+		assert.Equal(t, []int{runs[0].ID}, ids(second))
+		assert.Empty(t, second.NextCursor)
+	})
+}
+
+func Test_GetSyncRuns_InvalidParams(t *testing.T) {
+	_, e := setupTestApp(t)
+
+	tests := []struct {
+		name          string
+		query         string
+		expectedError string
+	}{
+		{name: "invalid cursor", query: "cursor=not-a-cursor", expectedError: "Invalid cursor"},
+		{name: "cursor without an id", query: "cursor=" + (pagination.Cursor{}).Encode(), expectedError: "Invalid cursor"},
+		{name: "invalid limit", query: "limit=201", expectedError: "Invalid limit, must be between 1 and 200"},
+		{name: "invalid league_id", query: "league_id=premier", expectedError: "Invalid league_id"},
+		{name: "invalid status", query: "status=done", expectedError: "Invalid status, must be running, succeeded or failed"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := request(e, http.MethodGet, "/v1/admin/sync-runs?"+test.query, "")
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			var response map[string]string
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, test.expectedError, response["error"])
+		})
+	}
+}
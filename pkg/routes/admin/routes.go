@@ -0,0 +1,28 @@
+package admin
+
+import (
+	"mike/pkg/application"
+	"mike/pkg/auth"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterRoutes(e *echo.Echo, app *application.App) {
+	e.POST("/v1/admin/api-keys", CreateAPIKey, auth.RequireScope(auth.ScopeAdmin))
+	e.GET("/v1/admin/api-keys", GetAPIKeys, auth.RequireScope(auth.ScopeAdmin))
+	e.DELETE("/v1/admin/api-keys/:id", RevokeAPIKey, auth.RequireScope(auth.ScopeAdmin))
+	e.POST("/v1/admin/sports", CreateSport, auth.RequireScope(auth.ScopeAdmin))
+	e.PATCH("/v1/admin/sports/:id", UpdateSport, auth.RequireScope(auth.ScopeAdmin))
+	e.DELETE("/v1/admin/sports/:id", DeleteSport, auth.RequireScope(auth.ScopeAdmin))
+	e.POST("/v1/admin/teams", CreateTeam, auth.RequireScope(auth.ScopeAdmin))
+	e.PATCH("/v1/admin/teams/:id", UpdateTeam, auth.RequireScope(auth.ScopeAdmin))
+	e.DELETE("/v1/admin/teams/:id", DeleteTeam, auth.RequireScope(auth.ScopeAdmin))
+	e.POST("/v1/admin/fixtures", CreateFixture, auth.RequireScope(auth.ScopeAdmin))
+	e.PATCH("/v1/admin/fixtures/:id", UpdateFixture, auth.RequireScope(auth.ScopeAdmin))
+	e.DELETE("/v1/admin/fixtures/:id", DeleteFixture, auth.RequireScope(auth.ScopeAdmin))
+	e.GET("/v1/admin/sync-runs", GetSyncRuns, auth.RequireScope(auth.ScopeAdmin))
+	e.POST("/v1/admin/team-aliases", CreateTeamAlias, auth.RequireScope(auth.ScopeAdmin))
+	e.GET("/v1/admin/team-alias-reviews", GetTeamAliasReviews, auth.RequireScope(auth.ScopeAdmin))
+	e.POST("/v1/admin/team-alias-reviews/:id/approve", ApproveTeamAliasReview, auth.RequireScope(auth.ScopeAdmin))
+	e.POST("/v1/admin/team-alias-reviews/:id/reject", RejectTeamAliasReview, auth.RequireScope(auth.ScopeAdmin))
+}
@@ -0,0 +1,110 @@
+package admin
+
+import (
+	"mike/pkg/application"
+	sportModels "mike/pkg/routes/sports/models"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SportRequest is the body of CreateSport and UpdateSport; fields left out of
+// an update keep their value
+type SportRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	ImageURL    *string `json:"image_url"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+// apply copies the fields set in req onto sport
+func (req SportRequest) apply(sport *sportModels.Sport) {
+	if req.Name != nil {
+		sport.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		sport.Description = *req.Description
+	}
+	if req.ImageURL != nil {
+		sport.ImageURL = *req.ImageURL
+	}
+	if req.IsActive != nil {
+		sport.IsActive = *req.IsActive
+	}
+}
+
+func CreateSport(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	var req SportRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	sport := sportModels.Sport{IsActive: true}
+	req.apply(&sport)
+	if sport.Name == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "name is required"})
+	}
+	if err := sportModels.CreateSport(app.DB, &sport); err != nil {
+		if err.Error() == "sport already exists" {
+			return c.JSON(http.StatusConflict, map[string]string{"error": "A sport with that name already exists"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create sport"})
+	}
+	return c.JSON(http.StatusCreated, sport)
+}
+
+func UpdateSport(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	sportID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid sport ID"})
+	}
+	var req SportRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	sport, err := sportModels.GetSportDetails(app.DB, sportID)
+	if err != nil {
+		if err.Error() == "sport not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Sport not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get sport details"})
+	}
+	req.apply(&sport)
+	if sport.Name == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "name can't be empty"})
+	}
+
+	sport, err = sportModels.UpdateSport(app.DB, sport)
+	if err != nil {
+		switch err.Error() {
+		case "sport not found":
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Sport not found"})
+		case "sport already exists":
+			return c.JSON(http.StatusConflict, map[string]string{"error": "A sport with that name already exists"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update sport"})
+	}
+	return c.JSON(http.StatusOK, sport)
+}
+
+// DeleteSport soft deletes a sport that has no teams left
+func DeleteSport(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	sportID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid sport ID"})
+	}
+	if err := sportModels.DeleteSport(app.DB, sportID); err != nil {
+		switch err.Error() {
+		case "sport not found":
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Sport not found"})
+		case "sport has teams":
+			return c.JSON(http.StatusConflict, map[string]string{"error": "Sport still has teams, delete them first"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete sport"})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
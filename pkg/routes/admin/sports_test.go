@@ -0,0 +1,124 @@
+package admin
+
+import (
+	"encoding/json"
+	sportModels "mike/pkg/routes/sports/models"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// createSport creates a sport through the API and returns it
+func createSport(t *testing.T, e *echo.Echo, name string) sportModels.Sport {
+	rec := request(e, http.MethodPost, "/v1/admin/sports", `{"name": "`+name+`", "description": "Played with a ball"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var sport sportModels.Sport
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &sport))
+	return sport
+}
+
+func Test_CreateSport(t *testing.T) {
+	app, e := setupTestApp(t)
+	truncateTables(app.DB)
+	defer truncateTables(app.DB)
+
+	sport := createSport(t, e, " Test Sport Admin ")
+	assert.NotZero(t, sport.ID)
+	assert.Equal(t, "Test Sport Admin", sport.Name)
+	assert.True(t, sport.IsActive)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "invalid body", body: `{"name": `, expectedStatus: http.StatusBadRequest},
+		{name: "missing name", body: `{"description": "No name"}`, expectedStatus: http.StatusBadRequest},
+		{name: "blank name", body: `{"name": "  "}`, expectedStatus: http.StatusBadRequest},
+		{name: "name taken", body: `{"name": "Test Sport Admin"}`, expectedStatus: http.StatusConflict},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := request(e, http.MethodPost, "/v1/admin/sports", test.body)
+			assert.Equal(t, test.expectedStatus, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func Test_UpdateSport(t *testing.T) {
+	app, e := setupTestApp(t)
+	truncateTables(app.DB)
+	defer truncateTables(app.DB)
+
+	sport := createSport(t, e, "Test Sport Admin")
+	createSport(t, e, "Other Sport Admin")
+	path := "/v1/admin/sports/" + strconv.Itoa(sport.ID)
+
+	// Fields left out keep their value
+	rec := request(e, http.MethodPatch, path, `{"is_active": false}`)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var updated sportModels.Sport
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+	assert.Equal(t, "Test Sport Admin", updated.Name)
+	assert.Equal(t, "Played with a ball", updated.Description)
+	assert.False(t, updated.IsActive)
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "invalid id", path: "/v1/admin/sports/abc", body: `{}`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid body", path: path, body: `{"name": 1}`, expectedStatus: http.StatusBadRequest},
+		{name: "blank name", path: path, body: `{"name": ""}`, expectedStatus: http.StatusBadRequest},
+		{name: "missing sport", path: "/v1/admin/sports/999999", body: `{}`, expectedStatus: http.StatusNotFound},
+		{name: "name taken", path: path, body: `{"name": "Other Sport Admin"}`, expectedStatus: http.StatusConflict},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := request(e, http.MethodPatch, test.path, test.body)
+			assert.Equal(t, test.expectedStatus, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func Test_DeleteSport(t *testing.T) {
+	app, e := setupTestApp(t)
+	truncateTables(app.DB)
+	defer truncateTables(app.DB)
+
+	sport := createSport(t, e, "Test Sport Admin")
+	team := createTeam(t, e, sport.ID, "Team Admin")
+	path := "/v1/admin/sports/" + strconv.Itoa(sport.ID)
+
+	rec := request(e, http.MethodDelete, path, "")
+	assert.Equal(t, http.StatusConflict, rec.Code, "a sport with teams can't be deleted")
+
+	rec = request(e, http.MethodDelete, "/v1/admin/teams/"+strconv.Itoa(team.ID), "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = request(e, http.MethodDelete, path, "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	// Deleted sports are gone from every read
+	rec = request(e, http.MethodGet, "/v1/sport/details/"+strconv.Itoa(sport.ID), "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = request(e, http.MethodGet, "/v1/sport/exists/"+strconv.Itoa(sport.ID), "")
+	assert.JSONEq(t, `{"exists": false}`, rec.Body.String())
+	rec = request(e, http.MethodGet, "/v1/sports", "")
+	assert.JSONEq(t, `[]`, rec.Body.String())
+
+	rec = request(e, http.MethodDelete, path, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code, "deleting twice")
+	rec = request(e, http.MethodPatch, path, `{"name": "Renamed"}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code, "updating a deleted sport")
+	rec = request(e, http.MethodDelete, "/v1/admin/sports/abc", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// A deleted sport's name can be used again
+	recreated := createSport(t, e, "Test Sport Admin")
+	assert.NotEqual(t, sport.ID, recreated.ID)
+}
@@ -0,0 +1,167 @@
+package admin
+
+import (
+	"mike/pkg/application"
+	sportModels "mike/pkg/routes/sports/models"
+	teamModels "mike/pkg/routes/teams/models"
+	venueModels "mike/pkg/routes/venues/models"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TeamRequest is the body of CreateTeam and UpdateTeam; fields left out of an
+// update keep their value, and a venue_id of 0 clears the venue
+type TeamRequest struct {
+	Name        *string `json:"name"`
+	SportID     *int    `json:"sport_id"`
+	Description *string `json:"description"`
+	ImageURL    *string `json:"image_url"`
+	IsActive    *bool   `json:"is_active"`
+	VenueID     *int    `json:"venue_id"`
+}
+
+// apply copies the fields set in req onto team
+func (req TeamRequest) apply(team *teamModels.Team) {
+	if req.Name != nil {
+		team.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.SportID != nil {
+		team.SportId = *req.SportID
+	}
+	if req.Description != nil {
+		team.Description = *req.Description
+	}
+	if req.ImageURL != nil {
+		team.ImageURL = *req.ImageURL
+	}
+	if req.IsActive != nil {
+		team.IsActive = *req.IsActive
+	}
+	if req.VenueID != nil {
+		team.VenueID = *req.VenueID
+	}
+}
+
+// validateTeam checks a team's name, and that its sport and venue exist,
+// returning the error message for a bad request
+func validateTeam(app *application.App, team teamModels.Team) (string, error) {
+	if team.Name == "" {
+		return "name is required", nil
+	}
+	if _, err := sportModels.GetSportDetails(app.DB, team.SportId); err != nil {
+		if err.Error() == "sport not found" {
+			return "Invalid sport_id", nil
+		}
+		return "", err
+	}
+	if team.VenueID != 0 {
+		if _, err := venueModels.GetVenueDetails(app.DB, team.VenueID); err != nil {
+			if err.Error() == "venue not found" {
+				return "Invalid venue_id", nil
+			}
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+func CreateTeam(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	var req TeamRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	team := teamModels.Team{IsActive: true}
+	req.apply(&team)
+	message, err := validateTeam(app, team)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to validate team"})
+	}
+	if message != "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": message})
+	}
+
+	if err := teamModels.CreateTeam(app.DB, &team); err != nil {
+		if err.Error() == "team already exists" {
+			return c.JSON(http.StatusConflict, map[string]string{"error": "A team with that name already exists in the sport"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create team"})
+	}
+	return c.JSON(http.StatusCreated, team)
+}
+
+func UpdateTeam(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	teamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid team ID"})
+	}
+	var req TeamRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	team, err := teamModels.GetTeamDetails(app.DB, teamID)
+	if err != nil {
+		if err.Error() == "team not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Team not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get team details"})
+	}
+	sportID := team.SportId
+	req.apply(&team)
+	team.Venue = nil
+	message, err := validateTeam(app, team)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to validate team"})
+	}
+	if message != "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": message})
+	}
+	// A team's fixtures must be in its sport
+	if team.SportId != sportID {
+		hasFixtures, err := teamModels.HasFixtures(app.DB, team.ID)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get team fixtures"})
+		}
+		if hasFixtures {
+			return c.JSON(http.StatusConflict, map[string]string{"error": "Team has fixtures in its current sport"})
+		}
+	}
+
+	if _, err := teamModels.UpdateTeam(app.DB, team); err != nil {
+		switch err.Error() {
+		case "team not found":
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Team not found"})
+		case "team already exists":
+			return c.JSON(http.StatusConflict, map[string]string{"error": "A team with that name already exists in the sport"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update team"})
+	}
+	team, err = teamModels.GetTeamDetails(app.DB, teamID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get team details"})
+	}
+	return c.JSON(http.StatusOK, team)
+}
+
+// DeleteTeam soft deletes a team that isn't in any fixture
+func DeleteTeam(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	teamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid team ID"})
+	}
+	if err := teamModels.DeleteTeam(app.DB, teamID); err != nil {
+		switch err.Error() {
+		case "team not found":
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Team not found"})
+		case "team has fixtures":
+			return c.JSON(http.StatusConflict, map[string]string{"error": "Team still has fixtures, delete them first"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete team"})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,142 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	teamModels "mike/pkg/routes/teams/models"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// createTeam creates a team through the API and returns it
+func createTeam(t *testing.T, e *echo.Echo, sportID int, name string) teamModels.Team {
+	rec := request(e, http.MethodPost, "/v1/admin/teams", fmt.Sprintf(`{"name": %q, "sport_id": %d}`, name, sportID))
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var team teamModels.Team
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &team))
+	return team
+}
+
+func Test_CreateTeam(t *testing.T) {
+	app, e := setupTestApp(t)
+	truncateTables(app.DB)
+	defer truncateTables(app.DB)
+
+	sport := createSport(t, e, "Test Sport Admin")
+	team := createTeam(t, e, sport.ID, "Team Admin")
+	assert.NotZero(t, team.ID)
+	assert.Equal(t, sport.ID, team.SportId)
+	assert.True(t, team.IsActive)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "invalid body", body: `[`, expectedStatus: http.StatusBadRequest},
+		{name: "missing name", body: fmt.Sprintf(`{"sport_id": %d}`, sport.ID), expectedStatus: http.StatusBadRequest},
+		{name: "missing sport", body: `{"name": "No Sport"}`, expectedStatus: http.StatusBadRequest},
+		{name: "unknown sport", body: `{"name": "Lost Team", "sport_id": 999999}`, expectedStatus: http.StatusBadRequest},
+		{name: "unknown venue", body: fmt.Sprintf(`{"name": "Homeless", "sport_id": %d, "venue_id": 999999}`, sport.ID), expectedStatus: http.StatusBadRequest},
+		{name: "name taken in the sport", body: fmt.Sprintf(`{"name": "Team Admin", "sport_id": %d}`, sport.ID), expectedStatus: http.StatusConflict},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := request(e, http.MethodPost, "/v1/admin/teams", test.body)
+			assert.Equal(t, test.expectedStatus, rec.Code, rec.Body.String())
+		})
+	}
+
+	// The same name is fine in another sport
+	other := createSport(t, e, "Other Sport Admin")
+	createTeam(t, e, other.ID, "Team Admin")
+}
+
+func Test_UpdateTeam(t *testing.T) {
+	app, e := setupTestApp(t)
+	truncateTables(app.DB)
+	defer truncateTables(app.DB)
+
+	sport := createSport(t, e, "Test Sport Admin")
+	other := createSport(t, e, "Other Sport Admin")
+	home := createTeam(t, e, sport.ID, "Home Admin")
+	away := createTeam(t, e, sport.ID, "Away Admin")
+	createFixture(t, e, sport.ID, home.ID, away.ID)
+	path := "/v1/admin/teams/" + strconv.Itoa(home.ID)
+
+	rec := request(e, http.MethodPatch, path, `{"description": "The home side"}`)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var updated teamModels.Team
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+	assert.Equal(t, "Home Admin", updated.Name)
+	assert.Equal(t, "The home side", updated.Description)
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "invalid id", path: "/v1/admin/teams/abc", body: `{}`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid body", path: path, body: `{"sport_id": "one"}`, expectedStatus: http.StatusBadRequest},
+		{name: "blank name", path: path, body: `{"name": " "}`, expectedStatus: http.StatusBadRequest},
+		{name: "unknown sport", path: path, body: `{"sport_id": 999999}`, expectedStatus: http.StatusBadRequest},
+		{name: "missing team", path: "/v1/admin/teams/999999", body: `{}`, expectedStatus: http.StatusNotFound},
+		{name: "name taken", path: path, body: `{"name": "Away Admin"}`, expectedStatus: http.StatusConflict},
+		{name: "moving sport with fixtures", path: path, body: fmt.Sprintf(`{"sport_id": %d}`, other.ID), expectedStatus: http.StatusConflict},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := request(e, http.MethodPatch, test.path, test.body)
+			assert.Equal(t, test.expectedStatus, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func Test_DeleteTeam(t *testing.T) {
+	app, e := setupTestApp(t)
+	truncateTables(app.DB)
+	defer truncateTables(app.DB)
+
+	sport := createSport(t, e, "Test Sport Admin")
+	home := createTeam(t, e, sport.ID, "Home Admin")
+	away := createTeam(t, e, sport.ID, "Away Admin")
+	fixture := createFixture(t, e, sport.ID, home.ID, away.ID)
+	path := "/v1/admin/teams/" + strconv.Itoa(home.ID)
+
+	rec := request(e, http.MethodDelete, path, "")
+	assert.Equal(t, http.StatusConflict, rec.Code, "a team with fixtures can't be deleted")
+
+	rec = request(e, http.MethodDelete, "/v1/admin/fixtures/"+strconv.Itoa(fixture.ID), "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = request(e, http.MethodDelete, path, "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	// Deleted teams are gone from every read
+	rec = request(e, http.MethodGet, "/v1/team/details/"+strconv.Itoa(home.ID), "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = request(e, http.MethodGet, "/v1/team/exists/"+strconv.Itoa(home.ID), "")
+	assert.JSONEq(t, `{"exists": false}`, rec.Body.String())
+	rec = request(e, http.MethodGet, fmt.Sprintf("/v1/teams?sport_id=%d", sport.ID), "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var page struct {
+		Data []teamModels.Team `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	if assert.Len(t, page.Data, 1) {
+		assert.Equal(t, away.ID, page.Data[0].ID)
+	}
+
+	rec = request(e, http.MethodDelete, path, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code, "deleting twice")
+	rec = request(e, http.MethodPatch, path, `{"name": "Renamed"}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code, "updating a deleted team")
+
+	// A deleted team's name can be used again
+	recreated := createTeam(t, e, sport.ID, "Home Admin")
+	assert.NotEqual(t, home.ID, recreated.ID)
+}
@@ -0,0 +1,147 @@
+package competitions
+
+import (
+	"mike/pkg/application"
+	"mike/pkg/pagination"
+	"mike/pkg/routes/competitions/models"
+	fixtureModels "mike/pkg/routes/fixtures/models"
+	sportModels "mike/pkg/routes/sports/models"
+	"mike/pkg/standings"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func GetCompetitions(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	sportID := 0
+	if raw := c.QueryParam("sport_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid sport_id"})
+		}
+		sportID = id
+	}
+	page, err := pagination.ParseParams(c.QueryParam("limit"), c.QueryParam("cursor"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": pagination.ErrorMessage(err)})
+	}
+	competitions, err := models.GetCompetitions(app.DB, sportID, page)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get competitions"})
+	}
+	return c.JSON(http.StatusOK, competitions)
+}
+
+func GetCompetitionSeasons(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	competitionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid competition ID"})
+	}
+	page, err := pagination.ParseParams(c.QueryParam("limit"), c.QueryParam("cursor"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": pagination.ErrorMessage(err)})
+	}
+	if _, err := models.GetCompetitionDetails(app.DB, competitionID); err != nil {
+		if err.Error() == "competition not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Competition not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get competition"})
+	}
+	seasons, err := models.GetSeasons(app.DB, competitionID, page)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get seasons"})
+	}
+	return c.JSON(http.StatusOK, seasons)
+}
+
+// GetSeasonFixtures lists a season's fixtures ordered by kickoff
+func GetSeasonFixtures(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	seasonID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid season ID"})
+	}
+	page, err := pagination.ParseTimeParams(c.QueryParam("limit"), c.QueryParam("cursor"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": pagination.ErrorMessage(err)})
+	}
+	if _, err := models.GetSeasonDetails(app.DB, seasonID); err != nil {
+		if err.Error() == "season not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Season not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get season"})
+	}
+	fixtures, _, err := fixtureModels.SearchFixtures(app.DB, fixtureModels.FixtureFilter{
+		SeasonID: seasonID,
+		Cursor:   page.Cursor,
+		Limit:    page.Limit,
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get fixtures"})
+	}
+	return c.JSON(http.StatusOK, fixtures)
+}
+
+type StandingsResponse struct {
+	CompetitionID int             `json:"competition_id"`
+	SeasonID      int             `json:"season_id"`
+	Season        string          `json:"season"`
+	Rules         standings.Rules `json:"rules"`
+	Standings     []standings.Row `json:"standings"`
+}
+
+// GetSeasonStandings computes a season's table from its finished fixtures
+func GetSeasonStandings(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	competitionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid competition ID"})
+	}
+	competition, err := models.GetCompetitionDetails(app.DB, competitionID)
+	if err != nil {
+		if err.Error() == "competition not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Competition not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get competition"})
+	}
+	season, err := models.GetSeasonByName(app.DB, competitionID, c.Param("season"))
+	if err != nil {
+		if err.Error() == "season not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Season not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get season"})
+	}
+
+	var rules standings.Rules
+	if competition.StandingsRules != nil {
+		rules = *competition.StandingsRules
+	} else {
+		sport, err := sportModels.GetSportDetails(app.DB, competition.SportID)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get sport"})
+		}
+		rules = standings.PresetFor(sport.Name)
+	}
+	if err := rules.Validate(); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Invalid standings rules: " + err.Error()})
+	}
+
+	teams, err := models.GetSeasonTeams(app.DB, season.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get teams"})
+	}
+	fixtures, err := fixtureModels.GetFinishedFixturesBySeason(app.DB, season.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get fixtures"})
+	}
+	return c.JSON(http.StatusOK, StandingsResponse{
+		CompetitionID: competition.ID,
+		SeasonID:      season.ID,
+		Season:        season.Name,
+		Rules:         rules,
+		Standings:     standings.Compute(fixtures, teams, rules),
+	})
+}
@@ -0,0 +1,236 @@
+package competitions
+
+import (
+	"context"
+	"encoding/json"
+	"mike/config"
+	"mike/pkg/application"
+	"mike/pkg/auth"
+	"mike/pkg/routes/competitions/models"
+	fixtureModels "mike/pkg/routes/fixtures/models"
+	"mike/pkg/standings"
+	"mike/utils"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+func setupTestApp(t *testing.T) (*application.App, *echo.Echo) {
+	cfg := config.GetConfig()
+	app, err := application.New(cfg)
+	assert.NoError(t, err, "Failed to create application")
+	app.DB = utils.GetDatabase()
+
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("app", app)
+			auth.SetKey(c, auth.APIKey{Scopes: []string{auth.ScopeReadCompetitions}})
+			return next(c)
+		}
+	})
+	RegisterRoutes(e, app)
+	return app, e
+}
+
+// testData is what setupTestData creates: two competitions of a soccer sport,
+// the second with its own standings rules, each with a 2025 season holding the
+// same four teams and results
+type testData struct {
+	// teams are the ids of teams A, B, C and D
+	teams [4]int
+	// competitions are the ids of the competition using the sport's preset
+	// rules and of the one with its own
+	competitions [2]int
+	seasons      [2]int
+}
+
+// setupTestData creates the test data and returns it with a cleanup function
+func setupTestData(t *testing.T, db *bun.DB) (testData, func()) {
+	ctx := context.Background()
+	truncate := func() {
+		for _, table := range []string{"fixtures", "season_teams", "seasons", "competitions", "teams", "sports"} {
+			_, _ = db.NewRaw("TRUNCATE TABLE " + table + " CASCADE").Exec(ctx)
+		}
+	}
+	truncate()
+
+	var data testData
+	sport := struct {
+		bun.BaseModel `bun:"sports"`
+		ID            int    `bun:"id,pk,autoincrement"`
+		Name          string `bun:"name"`
+		IsActive      bool   `bun:"is_active"`
+	}{Name: "Soccer", IsActive: true}
+	_, err := db.NewInsert().Model(&sport).Returning("id").Exec(ctx)
+	assert.NoError(t, err, "Error inserting sport")
+
+	for i, name := range []string{"Team A Standings", "Team B Standings", "Team C Standings", "Team D Standings"} {
+		team := struct {
+			bun.BaseModel `bun:"teams"`
+			ID            int    `bun:"id,pk,autoincrement"`
+			Name          string `bun:"name"`
+			SportId       int    `bun:"sport_id"`
+			IsActive      bool   `bun:"is_active"`
+		}{Name: name, SportId: sport.ID, IsActive: true}
+		_, err := db.NewInsert().Model(&team).Returning("id").Exec(ctx)
+		assert.NoError(t, err, "Error inserting team")
+		data.teams[i] = team.ID
+	}
+
+	// A point for any result but a defeat, with goals scored breaking ties
+	custom := standings.Rules{Win: 1, Draw: 1, Loss: 0, Tiebreakers: []string{standings.Points, standings.GoalsFor}}
+	for i, rules := range []*standings.Rules{nil, &custom} {
+		competition := models.Competition{SportID: sport.ID, Name: "Test League " + strconv.Itoa(i+1), StandingsRules: rules}
+		_, err := db.NewInsert().Model(&competition).Returning("id").Exec(ctx)
+		assert.NoError(t, err, "Error inserting competition")
+		season := models.Season{CompetitionID: competition.ID, Name: "2025"}
+		_, err = db.NewInsert().Model(&season).Returning("id").Exec(ctx)
+		assert.NoError(t, err, "Error inserting season")
+		data.competitions[i] = competition.ID
+		data.seasons[i] = season.ID
+	}
+
+	a, b, c, d := data.teams[0], data.teams[1], data.teams[2], data.teams[3]
+	kickoff := time.Date(2025, 8, 16, 14, 0, 0, 0, time.UTC)
+	results := []struct {
+		home, away, homeScore, awayScore int
+	}{
+		{a, b, 1, 0},
+		{c, d, 3, 3},
+		{b, c, 4, 0},
+		{d, a, 0, 0},
+	}
+	for _, seasonID := range data.seasons {
+		for i, result := range results {
+			homeScore, awayScore := result.homeScore, result.awayScore
+			fixture := fixtureModels.Fixture{
+				SportID: sport.ID, TeamID1: result.home, TeamID2: result.away, SeasonID: seasonID,
+				DateTime:  kickoff.Add(time.Duration(i) * 24 * time.Hour),
+				Status:    fixtureModels.StatusFinished,
+				HomeScore: &homeScore, AwayScore: &awayScore,
+			}
+			_, err := db.NewInsert().Model(&fixture).Exec(ctx)
+			assert.NoError(t, err, "Error inserting fixture")
+		}
+		// Not played yet, so left out of the table
+		upcoming := fixtureModels.Fixture{
+			SportID: sport.ID, TeamID1: b, TeamID2: a, SeasonID: seasonID,
+			DateTime: kickoff.Add(30 * 24 * time.Hour),
+			Status:   fixtureModels.StatusScheduled,
+		}
+		_, err := db.NewInsert().Model(&upcoming).Exec(ctx)
+		assert.NoError(t, err, "Error inserting fixture")
+		kickoff = kickoff.Add(7 * 24 * time.Hour)
+	}
+	return data, truncate
+}
+
+// get requests path and returns the response
+func get(e *echo.Echo, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func Test_GetSeasonStandings(t *testing.T) {
+	app, e := setupTestApp(t)
+	data, teardown := setupTestData(t, app.DB)
+	defer teardown()
+	a, b, c, d := data.teams[0], data.teams[1], data.teams[2], data.teams[3]
+
+	tests := []struct {
+		name           string
+		competitionID  int
+		seasonID       int
+		expectedRules  standings.Rules
+		expectedOrder  []int
+		expectedPoints []int
+	}{
+		{
+			// Three points for a win, then goal difference
+			name:           "sport preset",
+			competitionID:  data.competitions[0],
+			seasonID:       data.seasons[0],
+			expectedRules:  standings.Presets["soccer"],
+			expectedOrder:  []int{a, b, d, c},
+			expectedPoints: []int{4, 3, 2, 1},
+		},
+		{
+			// Level on points, D and B score more than A and C
+			name:           "competition rules",
+			competitionID:  data.competitions[1],
+			seasonID:       data.seasons[1],
+			expectedRules:  standings.Rules{Win: 1, Draw: 1, Loss: 0, Tiebreakers: []string{standings.Points, standings.GoalsFor}},
+			expectedOrder:  []int{d, a, b, c},
+			expectedPoints: []int{2, 2, 1, 1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := get(e, "/v1/competitions/"+strconv.Itoa(test.competitionID)+"/seasons/2025/standings")
+			assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+			var response StandingsResponse
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, test.competitionID, response.CompetitionID)
+			assert.Equal(t, test.seasonID, response.SeasonID)
+			assert.Equal(t, "2025", response.Season)
+			assert.Equal(t, test.expectedRules, response.Rules)
+
+			var order, points []int
+			for i, row := range response.Standings {
+				assert.Equal(t, i+1, row.Position)
+				assert.Equal(t, 2, row.Played, "the scheduled fixture isn't counted")
+				order = append(order, row.TeamID)
+				points = append(points, row.Points)
+			}
+			assert.Equal(t, test.expectedOrder, order)
+			assert.Equal(t, test.expectedPoints, points)
+		})
+	}
+}
+
+func Test_CompetitionsNotFound(t *testing.T) {
+	app, e := setupTestApp(t)
+	data, teardown := setupTestData(t, app.DB)
+	defer teardown()
+	competition := strconv.Itoa(data.competitions[0])
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{name: "seasons of unknown competition", path: "/v1/competitions/999999/seasons", expectedStatus: http.StatusNotFound, expectedError: "Competition not found"},
+		{name: "standings of unknown competition", path: "/v1/competitions/999999/seasons/2025/standings", expectedStatus: http.StatusNotFound, expectedError: "Competition not found"},
+		{name: "standings of unknown season", path: "/v1/competitions/" + competition + "/seasons/1999/standings", expectedStatus: http.StatusNotFound, expectedError: "Season not found"},
+		{name: "fixtures of unknown season", path: "/v1/seasons/999999/fixtures", expectedStatus: http.StatusNotFound, expectedError: "Season not found"},
+		{name: "invalid competition id", path: "/v1/competitions/abc/seasons", expectedStatus: http.StatusBadRequest, expectedError: "Invalid competition ID"},
+		{name: "invalid season id", path: "/v1/seasons/abc/fixtures", expectedStatus: http.StatusBadRequest, expectedError: "Invalid season ID"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := get(e, test.path)
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			var response map[string]string
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, test.expectedError, response["error"])
+		})
+	}
+
+	// Known competitions and seasons are found
+	rec := get(e, "/v1/competitions/"+competition+"/seasons")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"name":"2025"`)
+	rec = get(e, "/v1/seasons/"+strconv.Itoa(data.seasons[0])+"/fixtures")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,196 @@
+// Competitions (leagues and cups) and their seasons
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"mike/pkg/pagination"
+	teamModels "mike/pkg/routes/teams/models"
+	"mike/pkg/standings"
+
+	"github.com/uptrace/bun"
+)
+
+type Competition struct {
+	bun.BaseModel `bun:"competitions"`
+	ID            int    `bun:"id,pk,autoincrement" json:"id"`
+	SportID       int    `bun:"sport_id" json:"sport_id"`
+	Name          string `bun:"name" json:"name"`
+	// Provider and ExternalID identify competitions imported from a data provider
+	Provider   string `bun:"provider,nullzero" json:"provider,omitempty"`
+	ExternalID string `bun:"external_id,nullzero" json:"external_id,omitempty"`
+	// StandingsRules overrides the sport's default points and tiebreak rules
+	StandingsRules *standings.Rules `bun:"standings_rules,type:jsonb" json:"standings_rules,omitempty"`
+}
+
+type Season struct {
+	bun.BaseModel `bun:"seasons"`
+	ID            int    `bun:"id,pk,autoincrement" json:"id"`
+	CompetitionID int    `bun:"competition_id" json:"competition_id"`
+	Name          string `bun:"name" json:"name"`
+}
+
+func competitionCursor(competition Competition) pagination.Cursor {
+	return pagination.Cursor{ID: competition.ID}
+}
+
+func seasonCursor(season Season) pagination.Cursor {
+	return pagination.Cursor{ID: season.ID}
+}
+
+// GetCompetitions lists competitions ordered by id, only those of sportID when it isn't 0
+func GetCompetitions(db *bun.DB, sportID int, page pagination.Params) (pagination.Page[Competition], error) {
+	var competitions []Competition
+	q := db.NewSelect().Model(&competitions)
+	if sportID != 0 {
+		q = q.Where("sport_id = ?", sportID)
+	}
+	if page.Cursor != nil {
+		q = q.Where("id > ?", page.Cursor.ID)
+	}
+	err := q.OrderExpr("id ASC").Limit(page.Limit + 1).Scan(context.Background())
+	if err != nil {
+		return pagination.Page[Competition]{}, err
+	}
+	return pagination.NewPage(competitions, page.Limit, competitionCursor), nil
+}
+
+func GetCompetitionDetails(db *bun.DB, competitionID int) (Competition, error) {
+	var competition Competition
+	err := db.NewSelect().Model(&competition).Where("id = ?", competitionID).Scan(context.Background())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Competition{}, errors.New("competition not found")
+		}
+		return Competition{}, err
+	}
+	return competition, nil
+}
+
+// GetSeasons lists a competition's seasons ordered by id
+func GetSeasons(db *bun.DB, competitionID int, page pagination.Params) (pagination.Page[Season], error) {
+	var seasons []Season
+	q := db.NewSelect().Model(&seasons).Where("competition_id = ?", competitionID)
+	if page.Cursor != nil {
+		q = q.Where("id > ?", page.Cursor.ID)
+	}
+	err := q.OrderExpr("id ASC").Limit(page.Limit + 1).Scan(context.Background())
+	if err != nil {
+		return pagination.Page[Season]{}, err
+	}
+	return pagination.NewPage(seasons, page.Limit, seasonCursor), nil
+}
+
+func GetSeasonDetails(db *bun.DB, seasonID int) (Season, error) {
+	var season Season
+	err := db.NewSelect().Model(&season).Where("id = ?", seasonID).Scan(context.Background())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Season{}, errors.New("season not found")
+		}
+		return Season{}, err
+	}
+	return season, nil
+}
+
+// GetSeasonByName returns the competition's season with the given name, e.g. "2025"
+func GetSeasonByName(db *bun.DB, competitionID int, name string) (Season, error) {
+	var season Season
+	err := db.NewSelect().
+		Model(&season).
+		Where("competition_id = ? AND name = ?", competitionID, name).
+		Scan(context.Background())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Season{}, errors.New("season not found")
+		}
+		return Season{}, err
+	}
+	return season, nil
+}
+
+// GetProviderSeason returns the season of a competition imported from a
+// provider, found by the provider's own competition id
+func GetProviderSeason(db *bun.DB, provider string, externalID string, name string) (Season, error) {
+	var season Season
+	err := db.NewSelect().
+		Model(&season).
+		Join("JOIN competitions AS competition ON competition.id = season.competition_id").
+		Where("competition.provider = ? AND competition.external_id = ?", provider, externalID).
+		Where("season.name = ?", name).
+		Scan(context.Background())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Season{}, errors.New("season not found")
+		}
+		return Season{}, err
+	}
+	return season, nil
+}
+
+// GetSeasonTeams returns the names of the teams taking part in a season, keyed
+// by team id: its members, and anyone playing one of its fixtures
+func GetSeasonTeams(db *bun.DB, seasonID int) (map[int]string, error) {
+	var teams []teamModels.Team
+	err := db.NewSelect().
+		Model(&teams).
+		Column("id", "name").
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				Where("id IN (SELECT team_id FROM season_teams WHERE season_id = ?)", seasonID).
+				WhereOr("id IN (SELECT team_id_1 FROM fixtures WHERE season_id = ? AND deleted_at IS NULL)", seasonID).
+				WhereOr("id IN (SELECT team_id_2 FROM fixtures WHERE season_id = ? AND deleted_at IS NULL)", seasonID)
+		}).
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int]string, len(teams))
+	for _, team := range teams {
+		names[team.ID] = team.Name
+	}
+	return names, nil
+}
+
+// EnsureSeason returns the season of a provider competition, creating the
+// competition and season if they don't exist yet
+func EnsureSeason(ctx context.Context, db bun.IDB, competition Competition, seasonName string) (Season, error) {
+	// DO UPDATE rather than DO NOTHING so the id is returned for existing rows too
+	_, err := db.NewInsert().
+		Model(&competition).
+		On("CONFLICT (provider, external_id) DO UPDATE").
+		Set("name = EXCLUDED.name").
+		Set("updated_at = current_timestamp").
+		Returning("id").
+		Exec(ctx)
+	if err != nil {
+		return Season{}, err
+	}
+
+	season := Season{CompetitionID: competition.ID, Name: seasonName}
+	_, err = db.NewInsert().
+		Model(&season).
+		On("CONFLICT (competition_id, name) DO UPDATE").
+		Set("name = EXCLUDED.name").
+		Returning("id").
+		Exec(ctx)
+	if err != nil {
+		return Season{}, err
+	}
+	return season, nil
+}
+
+// AddSeasonTeams records that teams play in a season
+func AddSeasonTeams(ctx context.Context, db bun.IDB, seasonID int, teamIDs []int) error {
+	if len(teamIDs) == 0 {
+		return nil
+	}
+	_, err := db.NewRaw(
+		"INSERT INTO season_teams (season_id, team_id) "+
+			"SELECT ?, id FROM teams WHERE id IN (?) "+
+			"ON CONFLICT DO NOTHING",
+		seasonID, bun.In(teamIDs),
+	).Exec(ctx)
+	return err
+}
@@ -0,0 +1,15 @@
+package competitions
+
+import (
+	"mike/pkg/application"
+	"mike/pkg/auth"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterRoutes(e *echo.Echo, app *application.App) {
+	e.GET("/v1/competitions", GetCompetitions, auth.RequireScope(auth.ScopeReadCompetitions))
+	e.GET("/v1/competitions/:id/seasons", GetCompetitionSeasons, auth.RequireScope(auth.ScopeReadCompetitions))
+	e.GET("/v1/competitions/:id/seasons/:season/standings", GetSeasonStandings, auth.RequireScope(auth.ScopeReadCompetitions))
+	e.GET("/v1/seasons/:id/fixtures", GetSeasonFixtures, auth.RequireScope(auth.ScopeReadCompetitions))
+}
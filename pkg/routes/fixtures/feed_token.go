@@ -0,0 +1,27 @@
+package fixtures
+
+import (
+	"mike/pkg/application"
+	"mike/pkg/auth"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// FeedTokenResponse is a newly issued feed token, the only time it's shown
+type FeedTokenResponse struct {
+	Token string `json:"token"`
+}
+
+// CreateFeedToken issues a feed token for the request's key, for the feed
+// routes' query string. Issuing another replaces it, so a leaked token can be
+// cut off without revoking the key.
+func CreateFeedToken(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	apiKey, _ := auth.Key(c)
+	token, err := auth.CreateFeedToken(app.DB, apiKey.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create feed token"})
+	}
+	return c.JSON(http.StatusCreated, FeedTokenResponse{Token: token})
+}
@@ -2,8 +2,11 @@ package fixtures
 
 import (
 	"mike/pkg/application"
+	"mike/pkg/pagination"
 	"mike/pkg/routes/fixtures/models"
+	teamModels "mike/pkg/routes/teams/models"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -35,8 +38,10 @@ func parseFlexibleTime(timeStr string) (time.Time, error) {
 
 // TimeRangeRequest represents the JSON request body for date range queries
 type TimeRangeRequest struct {
-	Start string `json:"start"`
-	End   string `json:"end"`
+	Start  string `json:"start"`
+	End    string `json:"end"`
+	Limit  int    `json:"limit"`
+	Cursor string `json:"cursor"`
 }
 
 func GetFixturesByTimeRange(c echo.Context) error {
@@ -76,9 +81,275 @@ func GetFixturesByTimeRange(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Start time cannot be after end time"})
 	}
 
-	fixtures, err := models.GetFixturesByTimeRange(app.DB, startTimeTime, endTimeTime)
+	limit := ""
+	if req.Limit != 0 {
+		limit = strconv.Itoa(req.Limit)
+	}
+	// Clients not asking for pages get every fixture as a bare array, as before paging
+	paged := pagination.Paged(limit, req.Cursor)
+	var page pagination.Params
+	if paged {
+		page, err = pagination.ParseTimeParams(limit, req.Cursor)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": pagination.ErrorMessage(err)})
+		}
+	}
+
+	fixtures, err := models.GetFixturesByTimeRange(app.DB, startTimeTime, endTimeTime, page)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get fixtures"})
 	}
+	if !paged {
+		return c.JSON(http.StatusOK, fixtures.Data)
+	}
 	return c.JSON(http.StatusOK, fixtures)
 }
+
+// FixtureSearchResponse is one page of fixture search results
+type FixtureSearchResponse struct {
+	pagination.Page[models.Fixture]
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// parseIntParam reads an optional non-negative integer query parameter
+func parseIntParam(c echo.Context, name string, defaultVal int) (int, bool) {
+	raw := c.QueryParam(name)
+	if raw == "" {
+		return defaultVal, true
+	}
+	val, err := strconv.Atoi(raw)
+	if err != nil || val < 0 {
+		return 0, false
+	}
+	return val, true
+}
+
+// parseTimeParam reads an optional time query parameter
+func parseTimeParam(c echo.Context, name string) (*time.Time, bool) {
+	raw := c.QueryParam(name)
+	if raw == "" {
+		return nil, true
+	}
+	t, err := parseFlexibleTime(raw)
+	if err != nil {
+		return nil, false
+	}
+	return &t, true
+}
+
+func SearchFixtures(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+
+	var filter models.FixtureFilter
+	var ok bool
+	if filter.SportID, ok = parseIntParam(c, "sport_id", 0); !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid sport_id"})
+	}
+	if filter.SeasonID, ok = parseIntParam(c, "season_id", 0); !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid season_id"})
+	}
+	if filter.TeamID, ok = parseIntParam(c, "team_id", 0); !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid team_id"})
+	}
+	filter.Status = models.Status(c.QueryParam("status"))
+	if filter.Status != "" && !filter.Status.Valid() {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid status"})
+	}
+	if filter.Start, ok = parseTimeParam(c, "start"); !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid start time format"})
+	}
+	if filter.End, ok = parseTimeParam(c, "end"); !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid end time format"})
+	}
+	if filter.Start != nil && filter.End != nil && filter.Start.After(*filter.End) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Start time cannot be after end time"})
+	}
+	page, err := pagination.ParseTimeParams(c.QueryParam("limit"), c.QueryParam("cursor"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": pagination.ErrorMessage(err)})
+	}
+	filter.Limit = page.Limit
+	filter.Cursor = page.Cursor
+	if filter.Offset, ok = parseIntParam(c, "offset", 0); !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid offset"})
+	}
+	if filter.Cursor != nil && filter.Offset != 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Use either cursor or offset, not both"})
+	}
+
+	fixtures, total, err := models.SearchFixtures(app.DB, filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to search fixtures"})
+	}
+	return c.JSON(http.StatusOK, FixtureSearchResponse{
+		Page:   fixtures,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	})
+}
+
+func GetTeamFixturesCalendar(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	teamId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid team ID"})
+	}
+	team, err := teamModels.GetTeamDetails(app.DB, teamId)
+	if err != nil {
+		if err.Error() == "team not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Team not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get team details"})
+	}
+
+	fixtures, err := models.GetFixturesByTeam(app.DB, team.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get fixtures"})
+	}
+
+	calendar := renderCalendar(team.Name+" fixtures", fixtures, time.Now())
+	return c.Blob(http.StatusOK, "text/calendar; charset=utf-8", []byte(calendar))
+}
+
+// defaultScheduleFixtures is how many upcoming and past fixtures GetTeamSchedule returns by default
+const defaultScheduleFixtures = 5
+
+// TeamFixture is a fixture seen from one team's side
+type TeamFixture struct {
+	models.Fixture
+	Home     bool            `json:"home"`
+	Opponent teamModels.Team `json:"opponent"`
+}
+
+// TeamScheduleResponse is a team's next and previous fixtures, each ordered by kickoff
+type TeamScheduleResponse struct {
+	Team     teamModels.Team `json:"team"`
+	Upcoming []TeamFixture   `json:"upcoming"`
+	Past     []TeamFixture   `json:"past"`
+}
+
+// parseScheduleCount reads how many upcoming or past fixtures to return
+func parseScheduleCount(c echo.Context, name string) (int, bool) {
+	count, ok := parseIntParam(c, name, defaultScheduleFixtures)
+	if !ok || count > pagination.MaxLimit {
+		return 0, false
+	}
+	return count, true
+}
+
+func GetTeamSchedule(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	teamId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid team ID"})
+	}
+	upcoming, ok := parseScheduleCount(c, "upcoming")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid upcoming"})
+	}
+	past, ok := parseScheduleCount(c, "past")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid past"})
+	}
+
+	team, err := teamModels.GetTeamDetails(app.DB, teamId)
+	if err != nil {
+		if err.Error() == "team not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Team not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get team details"})
+	}
+
+	next, previous, err := models.GetTeamSchedule(app.DB, team.ID, time.Now(), upcoming, past)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get fixtures"})
+	}
+
+	var opponentIds []int
+	for _, fixture := range append(next, previous...) {
+		opponentIds = append(opponentIds, opponentOf(team.ID, fixture))
+	}
+	opponents, err := teamModels.GetTeamsByIDs(app.DB, opponentIds)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get team details"})
+	}
+
+	return c.JSON(http.StatusOK, TeamScheduleResponse{
+		Team:     team,
+		Upcoming: teamFixtures(team.ID, next, opponents),
+		Past:     teamFixtures(team.ID, previous, opponents),
+	})
+}
+
+// opponentOf returns the id of the team teamId plays in fixture
+func opponentOf(teamId int, fixture models.Fixture) int {
+	if fixture.TeamID1 == teamId {
+		return fixture.TeamID2
+	}
+	return fixture.TeamID1
+}
+
+// teamFixtures turns fixtures into teamId's side of them
+func teamFixtures(teamId int, fixtures []models.Fixture, opponents map[int]teamModels.Team) []TeamFixture {
+	out := make([]TeamFixture, len(fixtures))
+	for i, fixture := range fixtures {
+		opponentId := opponentOf(teamId, fixture)
+		opponent, ok := opponents[opponentId]
+		if !ok {
+			opponent = teamModels.Team{ID: opponentId}
+		}
+		out[i] = TeamFixture{Fixture: fixture, Home: fixture.TeamID1 == teamId, Opponent: opponent}
+	}
+	return out
+}
+
+// HeadToHeadResponse is every fixture between two teams, with the results
+// totalled from the first team's side
+type HeadToHeadResponse struct {
+	Team     teamModels.Team          `json:"team"`
+	Opponent teamModels.Team          `json:"opponent"`
+	Summary  models.HeadToHeadSummary `json:"summary"`
+	Fixtures []models.Fixture         `json:"fixtures"`
+}
+
+func GetHeadToHead(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	teamId, err := strconv.Atoi(c.Param("id1"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid team ID"})
+	}
+	opponentId, err := strconv.Atoi(c.Param("id2"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid team ID"})
+	}
+	if teamId == opponentId {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Teams must be different"})
+	}
+
+	teams := make([]teamModels.Team, 2)
+	for i, id := range []int{teamId, opponentId} {
+		team, err := teamModels.GetTeamDetails(app.DB, id)
+		if err != nil {
+			if err.Error() == "team not found" {
+				return c.JSON(http.StatusNotFound, map[string]string{"error": "Team not found"})
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get team details"})
+		}
+		teams[i] = team
+	}
+
+	fixtures, err := models.GetHeadToHead(app.DB, teamId, opponentId)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get fixtures"})
+	}
+
+	return c.JSON(http.StatusOK, HeadToHeadResponse{
+		Team:     teams[0],
+		Opponent: teams[1],
+		Summary:  models.SummarizeHeadToHead(teamId, fixtures),
+		Fixtures: fixtures,
+	})
+}
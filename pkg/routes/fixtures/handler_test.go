@@ -6,10 +6,13 @@ import (
 	"encoding/json"
 	"mike/config"
 	"mike/pkg/application"
+	"mike/pkg/auth"
+	"mike/pkg/pagination"
 	"mike/pkg/routes/fixtures/models"
 	"mike/utils"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
@@ -28,6 +31,7 @@ func setupTestApp(t *testing.T) (*application.App, *echo.Echo) {
 	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			c.Set("app", app)
+			auth.SetKey(c, auth.APIKey{Scopes: []string{auth.ScopeReadFixtures}})
 			return next(c)
 		}
 	})
@@ -35,6 +39,41 @@ func setupTestApp(t *testing.T) (*application.App, *echo.Echo) {
 	return app, e
 }
 
+// setupFeedTestApp serves the fixture routes behind the same authentication as
+// the server, and returns a feed token for a read:fixtures key with a cleanup
+// function
+func setupFeedTestApp(t *testing.T, app *application.App) (*echo.Echo, string, func()) {
+	truncate := func() {
+		_, _ = app.DB.NewRaw("TRUNCATE TABLE api_keys").Exec(context.Background())
+	}
+	truncate()
+
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("app", app)
+			return next(c)
+		}
+	})
+	e.Use(auth.AuthenticateFeeds(app.DB, FeedPaths...))
+	e.Use(auth.Authenticate(app.DB))
+	RegisterRoutes(e, app)
+
+	// Feed tokens are issued to a key sent in a header
+	apiKey := auth.APIKey{Owner: "Partner", Scopes: []string{auth.ScopeReadFixtures}}
+	key, err := auth.CreateKey(app.DB, &apiKey)
+	assert.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/v1/fixtures/feed-token", nil)
+	req.Header.Set("Authorization", "Bearer "+key)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var response FeedTokenResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.NotEmpty(t, response.Token)
+	return e, response.Token, truncate
+}
+
 // setupTestData creates test data and returns a cleanup function
 func setupTestData(t *testing.T, db *bun.DB) func() {
 	// Truncate all tables to ensure clean state
@@ -247,15 +286,61 @@ func Test_GetFixturesByTimeRange(t *testing.T) {
 			assert.Equal(t, test.expectedStatus, rec.Code, "HTTP status code should match expected")
 
 			if test.expectedStatus == http.StatusOK {
+				// Without a limit or cursor every fixture comes back as a bare array
 				var result []models.Fixture
 				err = json.Unmarshal(rec.Body.Bytes(), &result)
-				assert.NoError(t, err, "Response should be valid JSON")
+				assert.NoError(t, err, "Response should be a JSON array")
 				assert.Len(t, result, test.expectedCount, "Number of fixtures should match expected")
 			}
 		})
 	}
 }
 
+func Test_GetFixturesByTimeRange_Paged(t *testing.T) {
+	app, e := setupTestApp(t)
+	baseTime := time.Unix(1609459200, 0).UTC() // Jan 1, 2021 00:00:00 UTC
+	teardown := setupTestData(t, app.DB)
+	defer teardown()
+
+	request := func(body TimeRangeRequest) *httptest.ResponseRecorder {
+		jsonBody, err := json.Marshal(body)
+		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/v1/fixtures/daterange", bytes.NewReader(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("app", app)
+		assert.NoError(t, GetFixturesByTimeRange(c))
+		return rec
+	}
+
+	body := TimeRangeRequest{
+		Start: baseTime.Add(-48 * time.Hour).Format(time.RFC3339),
+		End:   baseTime.Add(24 * time.Hour).Format(time.RFC3339),
+		Limit: 2,
+	}
+	rec := request(body)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var first pagination.Page[models.Fixture]
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &first))
+	assert.Len(t, first.Data, 2)
+	assert.NotEmpty(t, first.NextCursor)
+
+	// A cursor alone asks for pages too
+	body.Limit = 0
+	body.Cursor = first.NextCursor
+	rec = request(body)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var second pagination.Page[models.Fixture]
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &second))
+	assert.Len(t, second.Data, 1)
+	assert.Empty(t, second.NextCursor)
+
+	body.Cursor = "not-a-cursor"
+	rec = request(body)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
 func Test_GetFixturesByTimeRange_AcceptsDifferentTimeFormats(t *testing.T) {
 	app, e := setupTestApp(t)
 
@@ -332,3 +417,295 @@ func Test_GetFixturesByTimeRange_AcceptsDifferentTimeFormats(t *testing.T) {
 		})
 	}
 }
+
+func Test_SearchFixtures(t *testing.T) {
+	app, e := setupTestApp(t)
+
+	// Setup test data and defer cleanup
+	teardown := setupTestData(t, app.DB)
+	defer teardown()
+
+	// Team 2 plays as team_id_2 in the first fixture and team_id_1 in the second
+	var team2ID int
+	err := app.DB.NewSelect().Model((*struct {
+		bun.BaseModel `bun:"teams"`
+		ID            int `bun:"id,pk,autoincrement"`
+	})(nil)).Column("id").Where("name = ?", "Team 2 Handler").Scan(context.Background(), &team2ID)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedCount  int
+		expectedTotal  int
+	}{
+		{
+			name:           "no filters returns every fixture",
+			query:          "",
+			expectedStatus: http.StatusOK,
+			expectedCount:  3,
+			expectedTotal:  3,
+		},
+		{
+			name:           "team filter matches either side",
+			query:          "team_id=" + strconv.Itoa(team2ID),
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+			expectedTotal:  2,
+		},
+		{
+			name:           "status filter",
+			query:          "status=finished",
+			expectedStatus: http.StatusOK,
+			expectedCount:  0,
+			expectedTotal:  0,
+		},
+		{
+			name:           "time range filter",
+			query:          "start=2021-01-01T00:00:00Z&end=2021-01-02T00:00:00Z",
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+			expectedTotal:  2,
+		},
+		{
+			name:           "limit and offset page through results",
+			query:          "limit=2&offset=2",
+			expectedStatus: http.StatusOK,
+			expectedCount:  1,
+			expectedTotal:  3,
+		},
+		{
+			name:           "invalid sport_id",
+			query:          "sport_id=abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid cursor",
+			query:          "cursor=not-a-cursor",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "cursor without a time",
+			query:          "cursor=" + pagination.Cursor{ID: 1}.Encode(),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "limit above maximum",
+			query:          "limit=1000",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "start after end",
+			query:          "start=2021-01-02&end=2021-01-01",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/fixtures?"+test.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/v1/fixtures")
+			c.Set("app", app)
+
+			err := SearchFixtures(c)
+			assert.NoError(t, err, "Handler should not return error")
+			assert.Equal(t, test.expectedStatus, rec.Code, "HTTP status code should match expected")
+
+			if test.expectedStatus == http.StatusOK {
+				var result FixtureSearchResponse
+				err = json.Unmarshal(rec.Body.Bytes(), &result)
+				assert.NoError(t, err, "Response should be valid JSON")
+				assert.Len(t, result.Data, test.expectedCount, "Number of fixtures should match expected")
+				assert.Equal(t, test.expectedTotal, result.Total, "Total should match expected")
+			}
+		})
+	}
+}
+
+func Test_GetHeadToHead(t *testing.T) {
+	app, e := setupTestApp(t)
+
+	// Setup test data and defer cleanup
+	teardown := setupTestData(t, app.DB)
+	defer teardown()
+
+	teamIDs := map[string]string{}
+	for _, name := range []string{"Team 1 Handler", "Team 2 Handler", "Team 4 Handler"} {
+		var teamID int
+		err := app.DB.NewSelect().Table("teams").Column("id").Where("name = ?", name).Scan(context.Background(), &teamID)
+		assert.NoError(t, err)
+		teamIDs[name] = strconv.Itoa(teamID)
+	}
+
+	tests := []struct {
+		name             string
+		teamID           string
+		opponentID       string
+		expectedStatus   int
+		expectedFixtures int
+	}{
+		{
+			name:             "away team first",
+			teamID:           teamIDs["Team 2 Handler"],
+			opponentID:       teamIDs["Team 1 Handler"],
+			expectedStatus:   http.StatusOK,
+			expectedFixtures: 1,
+		},
+		{
+			name:             "teams that haven't met",
+			teamID:           teamIDs["Team 1 Handler"],
+			opponentID:       teamIDs["Team 4 Handler"],
+			expectedStatus:   http.StatusOK,
+			expectedFixtures: 0,
+		},
+		{
+			name:           "same team twice",
+			teamID:         teamIDs["Team 1 Handler"],
+			opponentID:     teamIDs["Team 1 Handler"],
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown opponent",
+			teamID:         teamIDs["Team 1 Handler"],
+			opponentID:     "0",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid team id",
+			teamID:         "abc",
+			opponentID:     teamIDs["Team 1 Handler"],
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/teams/"+test.teamID+"/vs/"+test.opponentID, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/v1/teams/:id1/vs/:id2")
+			c.SetParamNames("id1", "id2")
+			c.SetParamValues(test.teamID, test.opponentID)
+			c.Set("app", app)
+
+			err := GetHeadToHead(c)
+			assert.NoError(t, err, "Handler should not return error")
+			assert.Equal(t, test.expectedStatus, rec.Code, "HTTP status code should match expected")
+
+			if test.expectedStatus == http.StatusOK {
+				var response HeadToHeadResponse
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err, "Response should be valid JSON")
+				assert.Equal(t, test.teamID, strconv.Itoa(response.Team.ID))
+				assert.Equal(t, test.opponentID, strconv.Itoa(response.Opponent.ID))
+				assert.Len(t, response.Fixtures, test.expectedFixtures)
+				// Scheduled fixtures don't count towards the summary
+				assert.Equal(t, models.HeadToHeadSummary{}, response.Summary)
+			}
+		})
+	}
+}
+
+func Test_GetTeamSchedule(t *testing.T) {
+	app, e := setupTestApp(t)
+
+	// Setup test data and defer cleanup
+	teardown := setupTestData(t, app.DB)
+	defer teardown()
+
+	var teamID int
+	err := app.DB.NewSelect().Table("teams").Column("id").Where("name = ?", "Team 2 Handler").Scan(context.Background(), &teamID)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name              string
+		teamID            string
+		query             string
+		expectedStatus    int
+		expectedOpponents []string
+		expectedHome      []bool
+	}{
+		{
+			name:              "defaults",
+			teamID:            strconv.Itoa(teamID),
+			expectedStatus:    http.StatusOK,
+			expectedOpponents: []string{"Team 1 Handler", "Team 3 Handler"},
+			expectedHome:      []bool{false, true},
+		},
+		{
+			name:              "last fixture only",
+			teamID:            strconv.Itoa(teamID),
+			query:             "?past=1",
+			expectedStatus:    http.StatusOK,
+			expectedOpponents: []string{"Team 3 Handler"},
+			expectedHome:      []bool{true},
+		},
+		{
+			name:              "no past fixtures",
+			teamID:            strconv.Itoa(teamID),
+			query:             "?past=0",
+			expectedStatus:    http.StatusOK,
+			expectedOpponents: []string{},
+			expectedHome:      []bool{},
+		},
+		{
+			name:           "invalid upcoming",
+			teamID:         strconv.Itoa(teamID),
+			query:          "?upcoming=-1",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "past over the limit",
+			teamID:         strconv.Itoa(teamID),
+			query:          "?past=1000",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown team",
+			teamID:         "0",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid team id",
+			teamID:         "abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/team/"+test.teamID+"/fixtures"+test.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/v1/team/:id/fixtures")
+			c.SetParamNames("id")
+			c.SetParamValues(test.teamID)
+			c.Set("app", app)
+
+			err := GetTeamSchedule(c)
+			assert.NoError(t, err, "Handler should not return error")
+			assert.Equal(t, test.expectedStatus, rec.Code, "HTTP status code should match expected")
+
+			if test.expectedStatus == http.StatusOK {
+				var response TeamScheduleResponse
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err, "Response should be valid JSON")
+				assert.Equal(t, teamID, response.Team.ID)
+				// Every test fixture kicks off in 2021
+				assert.Empty(t, response.Upcoming)
+
+				opponents := []string{}
+				home := []bool{}
+				for _, fixture := range response.Past {
+					opponents = append(opponents, fixture.Opponent.Name)
+					home = append(home, fixture.Home)
+				}
+				assert.Equal(t, test.expectedOpponents, opponents)
+				assert.Equal(t, test.expectedHome, home)
+			}
+		})
+	}
+}
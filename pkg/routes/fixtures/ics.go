@@ -0,0 +1,109 @@
+package fixtures
+
+import (
+	"fmt"
+	"mike/pkg/routes/fixtures/models"
+	"strings"
+	"time"
+)
+
+const (
+	icsTimeFormat = "20060102T150405Z"
+	// icsLineLimit is the maximum line length in octets before folding (RFC 5545 3.1)
+	icsLineLimit = 75
+	// Providers only give a kickoff time, so every event is given a nominal length
+	icsEventDuration = 2 * time.Hour
+)
+
+// icsStatus maps a fixture status to the VEVENT STATUS property
+func icsStatus(status models.Status) string {
+	switch status {
+	case models.StatusPostponed, models.StatusSuspended:
+		return "TENTATIVE"
+	case models.StatusCancelled, models.StatusAbandoned:
+		return "CANCELLED"
+	default:
+		return "CONFIRMED"
+	}
+}
+
+// icsEscape escapes a TEXT property value (RFC 5545 3.3.11)
+func icsEscape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// writeICSLine writes a content line terminated by CRLF, folding it so no line
+// exceeds icsLineLimit octets without splitting a UTF-8 sequence.
+func writeICSLine(b *strings.Builder, line string) {
+	limit := icsLineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines lose one octet to the leading space
+		limit = icsLineLimit - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
+
+// fixtureSummary names a fixture as "Home vs Away"
+func fixtureSummary(fixture models.Fixture) string {
+	home, away := fixture.Details.HomeTeam, fixture.Details.AwayTeam
+	if home == "" {
+		home = "TBD"
+	}
+	if away == "" {
+		away = "TBD"
+	}
+	return fmt.Sprintf("%s vs %s", home, away)
+}
+
+// renderCalendar renders fixtures as an RFC 5545 calendar named calendarName
+func renderCalendar(calendarName string, fixtures []models.Fixture, now time.Time) string {
+	var b strings.Builder
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:-//Mike//Fixtures//EN")
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	writeICSLine(&b, "METHOD:PUBLISH")
+	writeICSLine(&b, "X-WR-CALNAME:"+icsEscape(calendarName))
+
+	stamp := now.UTC().Format(icsTimeFormat)
+	for _, fixture := range fixtures {
+		start := fixture.DateTime.UTC()
+		writeICSLine(&b, "BEGIN:VEVENT")
+		// The fixture id never changes, so calendar clients update the event in place
+		writeICSLine(&b, fmt.Sprintf("UID:fixture-%d@mike", fixture.ID))
+		writeICSLine(&b, "DTSTAMP:"+stamp)
+		writeICSLine(&b, "DTSTART:"+start.Format(icsTimeFormat))
+		writeICSLine(&b, "DTEND:"+start.Add(icsEventDuration).Format(icsTimeFormat))
+		writeICSLine(&b, "SUMMARY:"+icsEscape(fixtureSummary(fixture)))
+		if fixture.Venue != nil {
+			location := fixture.Venue.Name
+			if fixture.Venue.City != "" {
+				location += ", " + fixture.Venue.City
+			}
+			writeICSLine(&b, "LOCATION:"+icsEscape(location))
+		}
+		writeICSLine(&b, "STATUS:"+icsStatus(fixture.Status))
+		writeICSLine(&b, "END:VEVENT")
+	}
+
+	writeICSLine(&b, "END:VCALENDAR")
+	return b.String()
+}
@@ -0,0 +1,154 @@
+package fixtures
+
+import (
+	"context"
+	"mike/pkg/routes/fixtures/models"
+	venueModels "mike/pkg/routes/venues/models"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RenderCalendar(t *testing.T) {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	fixtures := []models.Fixture{
+		{
+			ID:       11,
+			DateTime: time.Date(2025, 8, 16, 14, 0, 0, 0, time.UTC),
+			Status:   models.StatusScheduled,
+			Details:  models.Details{HomeTeam: "Brighton & Hove Albion", AwayTeam: "Fulham, London"},
+			Venue:    &venueModels.Venue{Name: "American Express Stadium", City: "Falmer"},
+		},
+		{
+			ID:       12,
+			DateTime: time.Date(2025, 8, 23, 16, 30, 0, 0, time.UTC),
+			Status:   models.StatusPostponed,
+		},
+		{
+			ID:       13,
+			DateTime: time.Date(2025, 8, 30, 11, 30, 0, 0, time.UTC),
+			Status:   models.StatusCancelled,
+		},
+	}
+
+	calendar := renderCalendar("Fulham fixtures", fixtures, now)
+
+	assert.True(t, strings.HasPrefix(calendar, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(calendar, "END:VCALENDAR\r\n"))
+	assert.Equal(t, 3, strings.Count(calendar, "BEGIN:VEVENT\r\n"))
+	assert.Contains(t, calendar, "UID:fixture-11@mike\r\n")
+	assert.Contains(t, calendar, "DTSTAMP:20250801T120000Z\r\n")
+	assert.Contains(t, calendar, "DTSTART:20250816T140000Z\r\n")
+	assert.Contains(t, calendar, "DTEND:20250816T160000Z\r\n")
+	assert.Contains(t, calendar, `SUMMARY:Brighton & Hove Albion vs Fulham\, London`+"\r\n")
+	assert.Contains(t, calendar, "SUMMARY:TBD vs TBD\r\n")
+	assert.Contains(t, calendar, `LOCATION:American Express Stadium\, Falmer`+"\r\n")
+	assert.Equal(t, 1, strings.Count(calendar, "LOCATION:"))
+	assert.Contains(t, calendar, "STATUS:CONFIRMED\r\n")
+	assert.Contains(t, calendar, "STATUS:TENTATIVE\r\n")
+	assert.Contains(t, calendar, "STATUS:CANCELLED\r\n")
+}
+
+func Test_WriteICSLine_FoldsLongLines(t *testing.T) {
+	var b strings.Builder
+	writeICSLine(&b, "SUMMARY:"+strings.Repeat("é", 60))
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	assert.Greater(t, len(lines), 1, "line should be folded")
+	for i, line := range lines {
+		assert.LessOrEqual(t, len(line), icsLineLimit, "folded lines must fit the limit")
+		if i > 0 {
+			assert.True(t, strings.HasPrefix(line, " "), "continuation lines start with a space")
+		}
+	}
+
+	// Unfolding restores the original value
+	unfolded := strings.ReplaceAll(strings.TrimSuffix(b.String(), "\r\n"), "\r\n ", "")
+	assert.Equal(t, "SUMMARY:"+strings.Repeat("é", 60), unfolded)
+}
+
+func Test_GetTeamFixturesCalendar(t *testing.T) {
+	app, e := setupTestApp(t)
+
+	// Setup test data and defer cleanup
+	teardown := setupTestData(t, app.DB)
+	defer teardown()
+
+	var teamID int
+	err := app.DB.NewSelect().Table("teams").Column("id").Where("name = ?", "Team 2 Handler").Scan(context.Background(), &teamID)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		teamID         string
+		expectedStatus int
+		expectedEvents int
+	}{
+		{
+			name:           "team with fixtures",
+			teamID:         strconv.Itoa(teamID),
+			expectedStatus: http.StatusOK,
+			expectedEvents: 2,
+		},
+		{
+			name:           "unknown team",
+			teamID:         "0",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid team id",
+			teamID:         "abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/team/"+test.teamID+"/fixtures.ics", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/v1/team/:id/fixtures.ics")
+			c.SetParamNames("id")
+			c.SetParamValues(test.teamID)
+			c.Set("app", app)
+
+			err := GetTeamFixturesCalendar(c)
+			assert.NoError(t, err, "Handler should not return error")
+			assert.Equal(t, test.expectedStatus, rec.Code, "HTTP status code should match expected")
+
+			if test.expectedStatus == http.StatusOK {
+				assert.Equal(t, "text/calendar; charset=utf-8", rec.Header().Get("Content-Type"))
+				assert.Equal(t, test.expectedEvents, strings.Count(rec.Body.String(), "BEGIN:VEVENT"))
+			}
+		})
+	}
+}
+
+func Test_GetTeamFixturesCalendar_SubscribesWithFeedToken(t *testing.T) {
+	app, _ := setupTestApp(t)
+	teardown := setupTestData(t, app.DB)
+	defer teardown()
+	e, token, teardownKeys := setupFeedTestApp(t, app)
+	defer teardownKeys()
+
+	var teamID int
+	err := app.DB.NewSelect().Table("teams").Column("id").Where("name = ?", "Team 2 Handler").Scan(context.Background(), &teamID)
+	assert.NoError(t, err)
+	path := "/v1/team/" + strconv.Itoa(teamID) + "/fixtures.ics"
+
+	// Calendar apps only have the subscription URL, so send no headers at all
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path+"?token="+token, nil))
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "text/calendar; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, 2, strings.Count(rec.Body.String(), "BEGIN:VEVENT"))
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "the token is needed")
+}
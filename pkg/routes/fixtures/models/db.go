@@ -2,20 +2,575 @@ package models
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"mike/pkg/pagination"
+	venueModels "mike/pkg/routes/venues/models"
 	"time"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
 )
 
-func GetFixturesByTimeRange(db *bun.DB, startTime time.Time, endTime time.Time) ([]Fixture, error) {
+// fixtureCursor is the position of a fixture in (date_time, id) order
+func fixtureCursor(fixture Fixture) pagination.Cursor {
+	dateTime := fixture.DateTime
+	return pagination.Cursor{Time: &dateTime, ID: fixture.ID}
+}
+
+// afterCursor restricts q to fixtures that sort after cursor in (date_time, id) order
+func afterCursor(q *bun.SelectQuery, cursor *pagination.Cursor) *bun.SelectQuery {
+	if cursor == nil {
+		return q
+	}
+	return q.Where("(fixture.date_time, fixture.id) > (?, ?)", *cursor.Time, cursor.ID)
+}
+
+// withRelations loads each fixture's venue and its period scores in order. The
+// venue is joined, so fixture columns it shares, like id, must be qualified.
+func withRelations(q *bun.SelectQuery) *bun.SelectQuery {
+	return q.Relation("Venue").Relation("Scores", func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.OrderExpr("sequence ASC")
+	})
+}
+
+func GetFixturesByTimeRange(db *bun.DB, startTime time.Time, endTime time.Time, page pagination.Params) (pagination.Page[Fixture], error) {
+	var fixtures []Fixture
+	q := db.NewSelect().Model(&fixtures).Apply(withRelations).Where("date_time >= ? AND date_time <= ?", startTime, endTime)
+	q = afterCursor(q, page.Cursor).OrderExpr("fixture.date_time ASC, fixture.id ASC")
+	if page.Limit > 0 {
+		q = q.Limit(page.Limit + 1)
+	}
+	if err := q.Scan(context.Background()); err != nil {
+		return pagination.Page[Fixture]{}, err
+	}
+	return pagination.NewPage(fixtures, page.Limit, fixtureCursor), nil
+}
+
+// GetFixturesByTeam returns every fixture the team plays in, home or away, ordered by kickoff
+func GetFixturesByTeam(db *bun.DB, teamID int) ([]Fixture, error) {
+	var fixtures []Fixture
+	err := db.NewSelect().
+		Model(&fixtures).
+		Apply(withRelations).
+		Where("team_id_1 = ? OR team_id_2 = ?", teamID, teamID).
+		OrderExpr("fixture.date_time ASC, fixture.id ASC").
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return fixtures, nil
+}
+
+// GetTeamSchedule returns a team's next upcoming fixtures, kicking off at or
+// after now, and its most recent past ones. Both are ordered by kickoff.
+func GetTeamSchedule(db *bun.DB, teamID int, now time.Time, upcoming, past int) ([]Fixture, []Fixture, error) {
+	next := []Fixture{}
+	if upcoming > 0 {
+		err := db.NewSelect().
+			Model(&next).
+			Apply(withRelations).
+			Where("team_id_1 = ? OR team_id_2 = ?", teamID, teamID).
+			Where("fixture.date_time >= ?", now).
+			OrderExpr("fixture.date_time ASC, fixture.id ASC").
+			Limit(upcoming).
+			Scan(context.Background())
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	previous := []Fixture{}
+	if past > 0 {
+		err := db.NewSelect().
+			Model(&previous).
+			Apply(withRelations).
+			Where("team_id_1 = ? OR team_id_2 = ?", teamID, teamID).
+			Where("fixture.date_time < ?", now).
+			OrderExpr("fixture.date_time DESC, fixture.id DESC").
+			Limit(past).
+			Scan(context.Background())
+		if err != nil {
+			return nil, nil, err
+		}
+		// Fetched newest first to take the last few, returned oldest first
+		for i, j := 0, len(previous)-1; i < j; i, j = i+1, j-1 {
+			previous[i], previous[j] = previous[j], previous[i]
+		}
+	}
+	return next, previous, nil
+}
+
+// GetHeadToHead returns every fixture between two teams, whichever is at home,
+// ordered by kickoff
+func GetHeadToHead(db *bun.DB, teamID, opponentID int) ([]Fixture, error) {
+	var fixtures []Fixture
+	err := db.NewSelect().
+		Model(&fixtures).
+		Apply(withRelations).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				Where("team_id_1 = ? AND team_id_2 = ?", teamID, opponentID).
+				WhereOr("team_id_1 = ? AND team_id_2 = ?", opponentID, teamID)
+		}).
+		OrderExpr("fixture.date_time ASC, fixture.id ASC").
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return fixtures, nil
+}
+
+// HeadToHeadSummary totals the finished fixtures between two teams from one team's side
+type HeadToHeadSummary struct {
+	Played       int `json:"played"`
+	Wins         int `json:"wins"`
+	Draws        int `json:"draws"`
+	Losses       int `json:"losses"`
+	GoalsFor     int `json:"goals_for"`
+	GoalsAgainst int `json:"goals_against"`
+}
+
+// SummarizeHeadToHead totals teamID's results in fixtures; fixtures that
+// aren't finished, have no score or that teamID doesn't play in are ignored
+func SummarizeHeadToHead(teamID int, fixtures []Fixture) HeadToHeadSummary {
+	var summary HeadToHeadSummary
+	for _, fixture := range fixtures {
+		if fixture.Status != StatusFinished || fixture.HomeScore == nil || fixture.AwayScore == nil {
+			continue
+		}
+		var scored, conceded int
+		switch teamID {
+		case fixture.TeamID1:
+			scored, conceded = *fixture.HomeScore, *fixture.AwayScore
+		case fixture.TeamID2:
+			scored, conceded = *fixture.AwayScore, *fixture.HomeScore
+		default:
+			continue
+		}
+		summary.Played++
+		summary.GoalsFor += scored
+		summary.GoalsAgainst += conceded
+		switch {
+		case scored > conceded:
+			summary.Wins++
+		case scored < conceded:
+			summary.Losses++
+		default:
+			summary.Draws++
+		}
+	}
+	return summary
+}
+
+// GetFinishedFixturesBySeason returns a season's finished fixtures with their
+// scores, ordered by kickoff
+func GetFinishedFixturesBySeason(db *bun.DB, seasonID int) ([]Fixture, error) {
 	var fixtures []Fixture
-	err := db.NewSelect().Model(&fixtures).Where("date_time >= ? AND date_time <= ?", startTime, endTime).Scan(context.Background())
+	err := db.NewSelect().
+		Model(&fixtures).
+		Apply(withRelations).
+		Where("season_id = ? AND status = ?", seasonID, StatusFinished).
+		OrderExpr("fixture.date_time ASC, fixture.id ASC").
+		Scan(context.Background())
 	if err != nil {
 		return nil, err
 	}
 	return fixtures, nil
 }
 
+// CountFixturesInPlay counts a season's fixtures that kicked off within the last
+// window and haven't finished, i.e. those whose live state may still change.
+func CountFixturesInPlay(db *bun.DB, seasonID int, now time.Time, window time.Duration) (int, error) {
+	return db.NewSelect().
+		Model((*Fixture)(nil)).
+		Where("season_id = ?", seasonID).
+		Where("date_time BETWEEN ? AND ?", now.Add(-window), now).
+		Where("status NOT IN (?)", bun.In(notInPlayStatuses)).
+		Count(context.Background())
+}
+
+// FixtureFilter narrows a fixture search. Zero values are ignored.
+type FixtureFilter struct {
+	SportID  int
+	SeasonID int
+	// TeamID matches fixtures where the team plays on either side.
+	TeamID int
+	Status Status
+	Start  *time.Time
+	End    *time.Time
+	// Cursor resumes after a previous page; Offset is ignored when it is set.
+	Cursor *pagination.Cursor
+	Limit  int
+	Offset int
+}
+
+// SearchFixtures returns one page of fixtures matching filter, ordered by kickoff,
+// along with the total number of matching fixtures.
+func SearchFixtures(db *bun.DB, filter FixtureFilter) (pagination.Page[Fixture], int, error) {
+	var fixtures []Fixture
+	q := db.NewSelect().Model(&fixtures)
+	if filter.SportID != 0 {
+		q = q.Where("sport_id = ?", filter.SportID)
+	}
+	if filter.SeasonID != 0 {
+		q = q.Where("season_id = ?", filter.SeasonID)
+	}
+	if filter.TeamID != 0 {
+		q = q.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("team_id_1 = ?", filter.TeamID).WhereOr("team_id_2 = ?", filter.TeamID)
+		})
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if filter.Start != nil {
+		q = q.Where("date_time >= ?", *filter.Start)
+	}
+	if filter.End != nil {
+		q = q.Where("date_time <= ?", *filter.End)
+	}
+	// The total counts every match, so it is taken before applying the cursor.
+	total, err := q.Count(context.Background())
+	if err != nil {
+		return pagination.Page[Fixture]{}, 0, err
+	}
+	if filter.Cursor != nil {
+		q = afterCursor(q, filter.Cursor)
+	} else {
+		q = q.Offset(filter.Offset)
+	}
+	err = q.Apply(withRelations).
+		OrderExpr("fixture.date_time ASC, fixture.id ASC").
+		Limit(filter.Limit + 1).
+		Scan(context.Background())
+	if err != nil {
+		return pagination.Page[Fixture]{}, 0, err
+	}
+	return pagination.NewPage(fixtures, filter.Limit, fixtureCursor), total, nil
+}
+
+func GetFixture(db *bun.DB, fixtureID int) (Fixture, error) {
+	var fixture Fixture
+	err := db.NewSelect().
+		Model(&fixture).
+		Apply(withRelations).
+		Where("fixture.id = ?", fixtureID).
+		Scan(context.Background())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Fixture{}, errors.New("fixture not found")
+		}
+		return Fixture{}, err
+	}
+	return fixture, nil
+}
+
+// isUniqueViolation reports whether err is Postgres rejecting a duplicate
+func isUniqueViolation(err error) bool {
+	var pgErr pgdriver.Error
+	return errors.As(err, &pgErr) && pgErr.Field('C') == "23505"
+}
+
+// CreateFixture saves a fixture entered by hand rather than imported from a
+// provider, along with its period scores
+func CreateFixture(db *bun.DB, fixture *Fixture) error {
+	err := db.RunInTx(context.Background(), nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(fixture).Returning("*").Exec(ctx); err != nil {
+			return err
+		}
+		return saveScores(ctx, tx, fixture.ID, fixture.Scores)
+	})
+	if isUniqueViolation(err) {
+		return errors.New("fixture already exists")
+	}
+	return err
+}
+
+// UpdateFixture saves fixture's sport, teams, kickoff time, status, details, season,
+// venue and score, and replaces its period scores with fixture.Scores. The
+// status isn't checked with ValidateTransition, so a mistake such as a fixture
+// wrongly marked finished can be put right.
+func UpdateFixture(db *bun.DB, fixture Fixture) (Fixture, error) {
+	err := db.RunInTx(context.Background(), nil, func(ctx context.Context, tx bun.Tx) error {
+		err := tx.NewUpdate().
+			Model(&fixture).
+			Set("sport_id = ?sport_id").
+			Set("team_id_1 = ?team_id_1").
+			Set("team_id_2 = ?team_id_2").
+			Set("date_time = ?date_time").
+			Set("status = ?status").
+			Set("details = ?details").
+			Set("season_id = ?season_id").
+			Set("venue_id = ?venue_id").
+			Set("home_score = ?home_score").
+			Set("away_score = ?away_score").
+			Set("updated_at = current_timestamp").
+			WherePK().
+			Returning("*").
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+		return saveScores(ctx, tx, fixture.ID, fixture.Scores)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Fixture{}, errors.New("fixture not found")
+		}
+		if isUniqueViolation(err) {
+			return Fixture{}, errors.New("fixture already exists")
+		}
+		return Fixture{}, err
+	}
+	return fixture, nil
+}
+
+// saveScores replaces the period scores of a fixture
+func saveScores(ctx context.Context, tx bun.Tx, fixtureID int, scores []FixtureScore) error {
+	_, err := tx.NewDelete().
+		Model((*FixtureScore)(nil)).
+		Where("fixture_id = ?", fixtureID).
+		Exec(ctx)
+	if err != nil || len(scores) == 0 {
+		return err
+	}
+	rows := make([]FixtureScore, len(scores))
+	for i, score := range scores {
+		score.ID = 0
+		score.FixtureID = fixtureID
+		rows[i] = score
+	}
+	_, err = tx.NewInsert().Model(&rows).Exec(ctx)
+	return err
+}
+
+// DeleteFixture soft deletes a fixture
+func DeleteFixture(db *bun.DB, fixtureID int) error {
+	res, err := db.NewDelete().Model((*Fixture)(nil)).Where("id = ?", fixtureID).Exec(context.Background())
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return errors.New("fixture not found")
+	}
+	return nil
+}
+
+var ErrMissingExternalID = errors.New("fixture has no provider external id")
+
+// UpsertResult counts what an upsert did with each provider fixture
+type UpsertResult struct {
+	Inserted  int `json:"inserted"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	// Rejected lists status changes that weren't allowed. The rest of those
+	// fixtures was still saved, keeping their stored status.
+	Rejected []RejectedStatus `json:"rejected,omitempty"`
+}
+
+// RejectedStatus is a provider fixture whose status couldn't move from From to To
+type RejectedStatus struct {
+	ExternalID string `json:"external_id"`
+	From       Status `json:"from"`
+	To         Status `json:"to"`
+}
+
+// UpsertFixtures saves provider fixtures keyed by (provider, external_id), updating
+// the teams, kickoff time, status, score and details of fixtures that already
+// exist. A fixture's period scores are replaced when it has any. Status changes
+// ValidateTransition doesn't allow are left out and reported in the result.
+func UpsertFixtures(db *bun.DB, fixtures []Fixture) (UpsertResult, error) {
+	if len(fixtures) == 0 {
+		return UpsertResult{}, nil
+	}
+	fixtures, err := dedupeByExternalID(fixtures)
+	if err != nil {
+		return UpsertResult{}, err
+	}
+	for _, fixture := range fixtures {
+		if !fixture.Status.Valid() {
+			return UpsertResult{}, fmt.Errorf("%w %q for fixture %s", ErrInvalidStatus, fixture.Status, fixture.ExternalID)
+		}
+	}
+
+	var result UpsertResult
+	err = db.RunInTx(context.Background(), nil, func(ctx context.Context, tx bun.Tx) error {
+		// Fixtures ingested before external ids existed are matched on the old
+		// natural key and claimed, so they're updated rather than duplicated.
+		for _, fixture := range fixtures {
+			_, err := tx.NewUpdate().
+				Model((*Fixture)(nil)).
+				Set("provider = ?", fixture.Provider).
+				Set("external_id = ?", fixture.ExternalID).
+				Where("external_id IS NULL").
+				Where("sport_id = ? AND team_id_1 = ? AND team_id_2 = ? AND date_time = ?",
+					fixture.SportID, fixture.TeamID1, fixture.TeamID2, fixture.DateTime).
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		rejected, err := keepValidStatuses(ctx, tx, fixtures)
+		if err != nil {
+			return err
+		}
+		result.Rejected = rejected
+
+		// Rows are only returned when inserted or actually changed; xmax is 0
+		// for a freshly inserted row.
+		var written []struct {
+			ID       int  `bun:"id"`
+			Inserted bool `bun:"inserted"`
+		}
+		err = tx.NewInsert().
+			Model(&fixtures).
+			On("CONFLICT (provider, external_id) DO UPDATE").
+			Set("team_id_1 = EXCLUDED.team_id_1").
+			Set("team_id_2 = EXCLUDED.team_id_2").
+			Set("date_time = EXCLUDED.date_time").
+			Set("status = EXCLUDED.status").
+			Set("season_id = EXCLUDED.season_id").
+			Set("venue_id = COALESCE(EXCLUDED.venue_id, fixture.venue_id)").
+			Set("details = EXCLUDED.details").
+			Set("home_score = EXCLUDED.home_score").
+			Set("away_score = EXCLUDED.away_score").
+			Set("updated_at = current_timestamp").
+			Where("(fixture.team_id_1, fixture.team_id_2, fixture.date_time, fixture.status, fixture.season_id, fixture.venue_id, fixture.details, fixture.home_score, fixture.away_score) IS DISTINCT FROM "+
+				"(EXCLUDED.team_id_1, EXCLUDED.team_id_2, EXCLUDED.date_time, EXCLUDED.status, EXCLUDED.season_id, COALESCE(EXCLUDED.venue_id, fixture.venue_id), EXCLUDED.details, EXCLUDED.home_score, EXCLUDED.away_score)").
+			Returning("id, (xmax = 0) AS inserted").
+			Scan(ctx, &written)
+		if err != nil {
+			return err
+		}
+		for _, row := range written {
+			if row.Inserted {
+				result.Inserted++
+			} else {
+				result.Updated++
+			}
+		}
+		result.Unchanged = len(fixtures) - len(written)
+		return replaceScores(ctx, tx, fixtures)
+	})
+	return result, err
+}
+
+// keepValidStatuses checks each fixture's status change against its stored
+// status, reverting the fixture to the stored status when the change isn't allowed
+func keepValidStatuses(ctx context.Context, tx bun.Tx, fixtures []Fixture) ([]RejectedStatus, error) {
+	keys := make([][]string, 0, len(fixtures))
+	for _, fixture := range fixtures {
+		keys = append(keys, []string{fixture.Provider, fixture.ExternalID})
+	}
+	var stored []Fixture
+	err := tx.NewSelect().
+		Model(&stored).
+		Column("provider", "external_id", "status").
+		Where("(provider, external_id) IN (?)", bun.In(keys)).
+		For("UPDATE").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]Status, len(stored))
+	for _, fixture := range stored {
+		current[fixture.Provider+"/"+fixture.ExternalID] = fixture.Status
+	}
+
+	var rejected []RejectedStatus
+	for i := range fixtures {
+		from, ok := current[fixtures[i].Provider+"/"+fixtures[i].ExternalID]
+		if !ok {
+			continue
+		}
+		if err := ValidateTransition(from, fixtures[i].Status); err != nil {
+			rejected = append(rejected, RejectedStatus{ExternalID: fixtures[i].ExternalID, From: from, To: fixtures[i].Status})
+			fixtures[i].Status = from
+			fixtures[i].Details.Status = from
+		}
+	}
+	return rejected, nil
+}
+
+// replaceScores swaps the stored period scores of each provider fixture that
+// has scores for the ones given
+func replaceScores(ctx context.Context, tx bun.Tx, fixtures []Fixture) error {
+	var keys [][]string
+	for _, fixture := range fixtures {
+		if len(fixture.Scores) > 0 {
+			keys = append(keys, []string{fixture.Provider, fixture.ExternalID})
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	// Unchanged fixtures aren't returned by the upsert, so look up every id
+	var stored []Fixture
+	err := tx.NewSelect().
+		Model(&stored).
+		Column("id", "provider", "external_id").
+		Where("(provider, external_id) IN (?)", bun.In(keys)).
+		Scan(ctx)
+	if err != nil {
+		return err
+	}
+	ids := make(map[string]int, len(stored))
+	for _, fixture := range stored {
+		ids[fixture.Provider+"/"+fixture.ExternalID] = fixture.ID
+	}
+
+	var scores []FixtureScore
+	fixtureIDs := make([]int, 0, len(keys))
+	for _, fixture := range fixtures {
+		id, ok := ids[fixture.Provider+"/"+fixture.ExternalID]
+		if !ok || len(fixture.Scores) == 0 {
+			continue
+		}
+		fixtureIDs = append(fixtureIDs, id)
+		for _, score := range fixture.Scores {
+			score.ID = 0
+			score.FixtureID = id
+			scores = append(scores, score)
+		}
+	}
+	_, err = tx.NewDelete().
+		Model((*FixtureScore)(nil)).
+		Where("fixture_id IN (?)", bun.In(fixtureIDs)).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = tx.NewInsert().Model(&scores).Exec(ctx)
+	return err
+}
+
+// dedupeByExternalID keeps the last occurrence of each provider fixture, since a
+// single upsert statement can't update the same row twice.
+func dedupeByExternalID(fixtures []Fixture) ([]Fixture, error) {
+	type key struct{ provider, externalID string }
+	index := make(map[key]int, len(fixtures))
+	deduped := make([]Fixture, 0, len(fixtures))
+	for _, fixture := range fixtures {
+		if fixture.Provider == "" || fixture.ExternalID == "" {
+			return nil, ErrMissingExternalID
+		}
+		k := key{fixture.Provider, fixture.ExternalID}
+		if i, ok := index[k]; ok {
+			deduped[i] = fixture
+			continue
+		}
+		index[k] = len(deduped)
+		deduped = append(deduped, fixture)
+	}
+	return deduped, nil
+}
+
 type Fixture struct {
 	ID       int       `bun:"id,pk,autoincrement" json:"id"`
 	SportID  int       `bun:"sport_id" json:"sport_id"`
@@ -23,12 +578,40 @@ type Fixture struct {
 	TeamID2  int       `bun:"team_id_2" json:"team_id_2"`
 	DateTime time.Time `bun:"date_time" json:"date_time"`
 	Details  Details   `bun:"details" json:"details"`
-	Status   string    `bun:"status" json:"status"`
+	Status   Status    `bun:"status" json:"status"`
+	// Provider and ExternalID identify fixtures imported from a data provider
+	Provider   string `bun:"provider,nullzero" json:"provider,omitempty"`
+	ExternalID string `bun:"external_id,nullzero" json:"external_id,omitempty"`
+	// SeasonID is the competition season the fixture is played in, if known
+	SeasonID int                `bun:"season_id,nullzero" json:"season_id,omitempty"`
+	VenueID  int                `bun:"venue_id,nullzero" json:"venue_id,omitempty"`
+	Venue    *venueModels.Venue `bun:"rel:belongs-to,join:venue_id=id" json:"venue,omitempty"`
+	// HomeScore and AwayScore are the running or final score, nil before kickoff
+	HomeScore *int           `bun:"home_score" json:"home_score"`
+	AwayScore *int           `bun:"away_score" json:"away_score"`
+	Scores    []FixtureScore `bun:"rel:has-many,join:id=fixture_id" json:"scores,omitempty"`
+	// DeletedAt is set when the fixture is deleted, after which it's left out of every query
+	DeletedAt time.Time `bun:"deleted_at,soft_delete,nullzero" json:"-"`
+}
+
+// FixtureScore is the score for one period of a fixture. Periods are named by
+// the sport (halftime and fulltime in soccer, innings in baseball, periods in
+// hockey) and Sequence orders them.
+type FixtureScore struct {
+	bun.BaseModel `bun:"fixture_scores"`
+	ID            int    `bun:"id,pk,autoincrement" json:"-"`
+	FixtureID     int    `bun:"fixture_id" json:"-"`
+	Period        string `bun:"period" json:"period"`
+	Sequence      int    `bun:"sequence" json:"sequence"`
+	Home          int    `bun:"home" json:"home"`
+	Away          int    `bun:"away" json:"away"`
 }
 
 type Details struct {
 	HomeTeam string    `json:"home_team"`
 	AwayTeam string    `json:"away_team"`
 	DateTime time.Time `json:"date_time"`
-	Status   string    `json:"status"`
+	Status   Status    `json:"status"`
+	// ProviderStatus is the provider's own status code that Status was mapped from
+	ProviderStatus string `json:"provider_status,omitempty"`
 }
@@ -2,6 +2,7 @@ package models
 
 import (
 	"context"
+	"mike/pkg/pagination"
 	"mike/utils"
 	"testing"
 	"time"
@@ -76,18 +77,21 @@ func setupModelTestData(t *testing.T, db *bun.DB) func() {
 			TeamID1:  teamIDs[0],
 			TeamID2:  teamIDs[1],
 			DateTime: baseTime, // Base time
+			Status:   StatusScheduled,
 		},
 		{
 			SportID:  sportID,
 			TeamID1:  teamIDs[1],
 			TeamID2:  teamIDs[2],
 			DateTime: baseTime.Add(24 * time.Hour), // Base time + 24 hours
+			Status:   StatusScheduled,
 		},
 		{
 			SportID:  sportID,
 			TeamID1:  teamIDs[2],
 			TeamID2:  teamIDs[3],
 			DateTime: baseTime.Add(-48 * time.Hour), // Base time - 48 hours
+			Status:   StatusScheduled,
 		},
 	}
 
@@ -163,7 +167,7 @@ func Test_GetFixturesByTimeRange(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			fixtures, err := GetFixturesByTimeRange(db, test.startTime, test.endTime)
+			page, err := GetFixturesByTimeRange(db, test.startTime, test.endTime, pagination.Params{Limit: pagination.DefaultLimit})
 			if test.wantError {
 				assert.Error(t, err)
 				return
@@ -171,7 +175,277 @@ func Test_GetFixturesByTimeRange(t *testing.T) {
 
 			assert.NoError(t, err)
 
-			assert.Equal(t, test.expectedFixtureCount, len(fixtures))
+			assert.Equal(t, test.expectedFixtureCount, len(page.Data))
+			assert.Empty(t, page.NextCursor)
 		})
 	}
 }
+
+func Test_SearchFixtures(t *testing.T) {
+	db := utils.GetDatabase()
+
+	// Setup test data and defer cleanup
+	teardown := setupModelTestData(t, db)
+	defer teardown()
+
+	baseTime := time.Unix(1609459200, 0).UTC() // Jan 1, 2021 00:00:00 UTC
+	start := baseTime
+	end := baseTime.Add(24 * time.Hour)
+
+	tests := []struct {
+		name          string
+		filter        FixtureFilter
+		expectedCount int
+		expectedTotal int
+	}{
+		{
+			name:          "no filters",
+			filter:        FixtureFilter{},
+			expectedCount: 3,
+			expectedTotal: 3,
+		},
+		{
+			name:          "time window",
+			filter:        FixtureFilter{Start: &start, End: &end},
+			expectedCount: 2,
+			expectedTotal: 2,
+		},
+		{
+			name:          "limit keeps the total",
+			filter:        FixtureFilter{Limit: 1},
+			expectedCount: 1,
+			expectedTotal: 3,
+		},
+		{
+			name:          "unknown sport",
+			filter:        FixtureFilter{SportID: -1},
+			expectedCount: 0,
+			expectedTotal: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.filter.Limit == 0 {
+				test.filter.Limit = pagination.DefaultLimit
+			}
+			page, total, err := SearchFixtures(db, test.filter)
+			assert.NoError(t, err)
+			assert.Len(t, page.Data, test.expectedCount)
+			assert.Equal(t, test.expectedTotal, total)
+		})
+	}
+}
+
+func Test_GetFixturesByTimeRange_WalksPagesWithCursor(t *testing.T) {
+	db := utils.GetDatabase()
+
+	// Setup test data and defer cleanup
+	teardown := setupModelTestData(t, db)
+	defer teardown()
+
+	baseTime := time.Unix(1609459200, 0).UTC() // Jan 1, 2021 00:00:00 UTC
+	start := baseTime.Add(-48 * time.Hour)
+	end := baseTime.Add(24 * time.Hour)
+
+	var seen []time.Time
+	params := pagination.Params{Limit: 1}
+	for pages := 0; pages < 10; pages++ {
+		page, err := GetFixturesByTimeRange(db, start, end, params)
+		assert.NoError(t, err)
+		for _, fixture := range page.Data {
+			seen = append(seen, fixture.DateTime)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor, err := pagination.DecodeCursor(page.NextCursor)
+		assert.NoError(t, err)
+		params.Cursor = &cursor
+	}
+
+	// Every fixture is returned exactly once, in kickoff order
+	assert.Len(t, seen, 3)
+	for i := 1; i < len(seen); i++ {
+		assert.True(t, seen[i-1].Before(seen[i]), "fixtures should be ordered by date_time")
+	}
+}
+
+// modelTestTeamIDs returns the ids of the teams created by setupModelTestData
+func modelTestTeamIDs(t *testing.T, db *bun.DB) (sportID int, teamIDs []int) {
+	err := db.NewSelect().Table("sports").Column("id").Where("name = ?", "Test Sport Model").Scan(context.Background(), &sportID)
+	assert.NoError(t, err)
+	err = db.NewSelect().Table("teams").Column("id").Where("sport_id = ?", sportID).OrderExpr("name ASC").Scan(context.Background(), &teamIDs)
+	assert.NoError(t, err)
+	return sportID, teamIDs
+}
+
+func Test_UpsertFixtures(t *testing.T) {
+	db := utils.GetDatabase()
+
+	// Setup test data and defer cleanup
+	teardown := setupModelTestData(t, db)
+	defer teardown()
+
+	sportID, teamIDs := modelTestTeamIDs(t, db)
+	baseTime := time.Unix(1609459200, 0).UTC() // Jan 1, 2021 00:00:00 UTC
+	kickoff := baseTime.Add(72 * time.Hour)
+
+	countFixtures := func() int {
+		count, err := db.NewSelect().Model((*Fixture)(nil)).Count(context.Background())
+		assert.NoError(t, err)
+		return count
+	}
+	loadByExternalID := func(externalID string) Fixture {
+		var fixture Fixture
+		err := db.NewSelect().Model(&fixture).Where("provider = ? AND external_id = ?", "test-provider", externalID).Scan(context.Background())
+		assert.NoError(t, err)
+		return fixture
+	}
+
+	t.Run("inserts new provider fixtures", func(t *testing.T) {
+		result, err := UpsertFixtures(db, []Fixture{{
+			SportID: sportID, TeamID1: teamIDs[0], TeamID2: teamIDs[3], DateTime: kickoff,
+			Status: StatusScheduled, Provider: "test-provider", ExternalID: "1001",
+		}})
+		assert.NoError(t, err)
+		assert.Equal(t, UpsertResult{Inserted: 1}, result)
+		assert.Equal(t, 4, countFixtures())
+		assert.Equal(t, StatusScheduled, loadByExternalID("1001").Status)
+	})
+
+	t.Run("updates status and kickoff of an existing fixture in place", func(t *testing.T) {
+		rescheduled := kickoff.Add(24 * time.Hour)
+		result, err := UpsertFixtures(db, []Fixture{{
+			SportID: sportID, TeamID1: teamIDs[0], TeamID2: teamIDs[3], DateTime: rescheduled,
+			Status: StatusPostponed, Provider: "test-provider", ExternalID: "1001",
+		}})
+		assert.NoError(t, err)
+		assert.Equal(t, UpsertResult{Updated: 1}, result)
+		assert.Equal(t, 4, countFixtures())
+		fixture := loadByExternalID("1001")
+		assert.Equal(t, StatusPostponed, fixture.Status)
+		assert.True(t, rescheduled.Equal(fixture.DateTime))
+	})
+
+	t.Run("claims a fixture ingested before external ids", func(t *testing.T) {
+		// setupModelTestData inserts team 1 vs team 2 at base time without an external id
+		_, err := UpsertFixtures(db, []Fixture{{
+			SportID: sportID, TeamID1: teamIDs[0], TeamID2: teamIDs[1], DateTime: baseTime,
+			Status: StatusFinished, Provider: "test-provider", ExternalID: "2002",
+		}})
+		assert.NoError(t, err)
+		assert.Equal(t, 4, countFixtures())
+		assert.Equal(t, StatusFinished, loadByExternalID("2002").Status)
+	})
+
+	t.Run("stores the score and replaces period scores", func(t *testing.T) {
+		score := func(home, away int) (*int, *int) { return &home, &away }
+		fixture := Fixture{
+			SportID: sportID, TeamID1: teamIDs[0], TeamID2: teamIDs[3], DateTime: kickoff,
+			Status: StatusHalftime, Provider: "test-provider", ExternalID: "1001",
+			Scores: []FixtureScore{{Period: "halftime", Sequence: 1, Home: 1, Away: 0}},
+		}
+		fixture.HomeScore, fixture.AwayScore = score(1, 0)
+		result, err := UpsertFixtures(db, []Fixture{fixture})
+		assert.NoError(t, err)
+		assert.Equal(t, UpsertResult{Updated: 1}, result)
+
+		fixture.Status = StatusFinished
+		fixture.HomeScore, fixture.AwayScore = score(2, 1)
+		fixture.Scores = append(fixture.Scores, FixtureScore{Period: "fulltime", Sequence: 2, Home: 2, Away: 1})
+		_, err = UpsertFixtures(db, []Fixture{fixture})
+		assert.NoError(t, err)
+
+		stored, err := GetFixturesByTeam(db, teamIDs[3])
+		assert.NoError(t, err)
+		if assert.Len(t, stored, 1) {
+			assert.Equal(t, 2, *stored[0].HomeScore)
+			assert.Equal(t, 1, *stored[0].AwayScore)
+			if assert.Len(t, stored[0].Scores, 2) {
+				assert.Equal(t, "halftime", stored[0].Scores[0].Period)
+				assert.Equal(t, "fulltime", stored[0].Scores[1].Period)
+				assert.Equal(t, 2, stored[0].Scores[1].Home)
+			}
+		}
+	})
+
+	t.Run("keeps a finished fixture finished", func(t *testing.T) {
+		result, err := UpsertFixtures(db, []Fixture{{
+			SportID: sportID, TeamID1: teamIDs[0], TeamID2: teamIDs[1], DateTime: baseTime.Add(time.Hour),
+			Status: StatusScheduled, Provider: "test-provider", ExternalID: "2002",
+		}})
+		assert.NoError(t, err)
+		assert.Equal(t, []RejectedStatus{{ExternalID: "2002", From: StatusFinished, To: StatusScheduled}}, result.Rejected)
+		assert.Equal(t, 1, result.Updated, "the rest of the fixture is still saved")
+		fixture := loadByExternalID("2002")
+		assert.Equal(t, StatusFinished, fixture.Status)
+		assert.True(t, baseTime.Add(time.Hour).Equal(fixture.DateTime))
+	})
+
+	t.Run("rejects unknown statuses", func(t *testing.T) {
+		_, err := UpsertFixtures(db, []Fixture{{
+			SportID: sportID, TeamID1: teamIDs[0], TeamID2: teamIDs[1], DateTime: kickoff,
+			Status: "NS", Provider: "test-provider", ExternalID: "3003",
+		}})
+		assert.ErrorIs(t, err, ErrInvalidStatus)
+	})
+
+	t.Run("rejects fixtures without an external id", func(t *testing.T) {
+		_, err := UpsertFixtures(db, []Fixture{{SportID: sportID, TeamID1: teamIDs[0], TeamID2: teamIDs[1], DateTime: kickoff}})
+		assert.ErrorIs(t, err, ErrMissingExternalID)
+	})
+}
+
+func Test_SummarizeHeadToHead(t *testing.T) {
+	score := func(n int) *int { return &n }
+	fixtures := []Fixture{
+		{TeamID1: 1, TeamID2: 2, Status: StatusFinished, HomeScore: score(3), AwayScore: score(0)},
+		{TeamID1: 2, TeamID2: 1, Status: StatusFinished, HomeScore: score(3), AwayScore: score(1)},
+		{TeamID1: 2, TeamID2: 1, Status: StatusFinished, HomeScore: score(1), AwayScore: score(1)},
+		// Not played yet, or no score stored
+		{TeamID1: 1, TeamID2: 2, Status: StatusScheduled},
+		{TeamID1: 1, TeamID2: 2, Status: StatusFinished},
+	}
+
+	assert.Equal(t, HeadToHeadSummary{Played: 3, Wins: 1, Draws: 1, Losses: 1, GoalsFor: 5, GoalsAgainst: 4}, SummarizeHeadToHead(1, fixtures))
+	assert.Equal(t, HeadToHeadSummary{Played: 3, Wins: 1, Draws: 1, Losses: 1, GoalsFor: 4, GoalsAgainst: 5}, SummarizeHeadToHead(2, fixtures))
+	assert.Equal(t, HeadToHeadSummary{}, SummarizeHeadToHead(3, fixtures))
+}
+
+func Test_UpdateAndDeleteFixture(t *testing.T) {
+	db := utils.GetDatabase()
+	teardown := setupModelTestData(t, db)
+	defer teardown()
+
+	baseTime := time.Unix(1609459200, 0).UTC() // Jan 1, 2021 00:00:00 UTC
+	page, err := GetFixturesByTimeRange(db, baseTime, baseTime, pagination.Params{Limit: pagination.DefaultLimit})
+	assert.NoError(t, err)
+	assert.Len(t, page.Data, 1)
+	fixture := page.Data[0]
+
+	home, away := 2, 1
+	fixture.Status = StatusFinished
+	fixture.HomeScore, fixture.AwayScore = &home, &away
+	fixture.DateTime = baseTime.Add(time.Hour)
+	updated, err := UpdateFixture(db, fixture)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusFinished, updated.Status)
+	assert.Equal(t, baseTime.Add(time.Hour), updated.DateTime.UTC())
+	assert.Equal(t, 2, *updated.HomeScore)
+
+	assert.NoError(t, DeleteFixture(db, fixture.ID))
+	_, err = GetFixture(db, fixture.ID)
+	assert.EqualError(t, err, "fixture not found")
+	assert.EqualError(t, DeleteFixture(db, fixture.ID), "fixture not found")
+	_, err = UpdateFixture(db, fixture)
+	assert.EqualError(t, err, "fixture not found")
+
+	// Deleted fixtures are kept, but left out of every query
+	page, err = GetFixturesByTimeRange(db, baseTime, baseTime.Add(2*time.Hour), pagination.Params{Limit: pagination.DefaultLimit})
+	assert.NoError(t, err)
+	assert.Empty(t, page.Data)
+	deleted, err := db.NewSelect().Model((*Fixture)(nil)).WhereDeleted().Where("id = ?", fixture.ID).Count(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+}
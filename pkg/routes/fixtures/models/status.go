@@ -0,0 +1,66 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Status is where a fixture is in its lifecycle, the same for every sport.
+// Providers map their own status codes onto it and the raw code is kept in
+// Details.ProviderStatus.
+type Status string
+
+const (
+	StatusScheduled Status = "scheduled"
+	StatusLive      Status = "live"
+	StatusHalftime  Status = "halftime"
+	StatusFinished  Status = "finished"
+	StatusPostponed Status = "postponed"
+	StatusCancelled Status = "cancelled"
+	StatusAbandoned Status = "abandoned"
+	StatusSuspended Status = "suspended"
+)
+
+var (
+	ErrInvalidStatus     = errors.New("invalid fixture status")
+	ErrInvalidTransition = errors.New("invalid fixture status transition")
+)
+
+// statusTransitions lists the statuses a fixture may move to from each status.
+// Staying in the same status is always allowed.
+var statusTransitions = map[Status][]Status{
+	StatusScheduled: {StatusLive, StatusHalftime, StatusFinished, StatusPostponed, StatusCancelled, StatusAbandoned, StatusSuspended},
+	StatusPostponed: {StatusScheduled, StatusLive, StatusHalftime, StatusFinished, StatusCancelled, StatusAbandoned, StatusSuspended},
+	StatusLive:      {StatusHalftime, StatusFinished, StatusAbandoned, StatusSuspended},
+	StatusHalftime:  {StatusLive, StatusFinished, StatusAbandoned, StatusSuspended},
+	StatusSuspended: {StatusLive, StatusHalftime, StatusFinished, StatusPostponed, StatusCancelled, StatusAbandoned},
+	// An abandoned match may be replayed from the start or have a result awarded
+	StatusAbandoned: {StatusScheduled, StatusPostponed, StatusLive, StatusFinished},
+	StatusFinished:  {},
+	StatusCancelled: {},
+}
+
+// Valid reports whether s is one of the canonical statuses
+func (s Status) Valid() bool {
+	_, ok := statusTransitions[s]
+	return ok
+}
+
+// ValidateTransition checks that a fixture may move from one status to another
+func ValidateTransition(from, to Status) error {
+	if !from.Valid() || !to.Valid() {
+		return fmt.Errorf("%w: %q -> %q", ErrInvalidStatus, from, to)
+	}
+	if from == to {
+		return nil
+	}
+	for _, allowed := range statusTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, to)
+}
+
+// notInPlayStatuses are the statuses of fixtures that have ended or won't be played
+var notInPlayStatuses = []Status{StatusFinished, StatusPostponed, StatusCancelled, StatusAbandoned}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ValidateTransition(t *testing.T) {
+	tests := []struct {
+		from, to Status
+		err      error
+	}{
+		{StatusScheduled, StatusLive, nil},
+		{StatusLive, StatusHalftime, nil},
+		{StatusHalftime, StatusLive, nil},
+		{StatusLive, StatusFinished, nil},
+		{StatusPostponed, StatusScheduled, nil},
+		{StatusSuspended, StatusLive, nil},
+		{StatusAbandoned, StatusScheduled, nil},
+		{StatusFinished, StatusFinished, nil},
+		{StatusFinished, StatusScheduled, ErrInvalidTransition},
+		{StatusFinished, StatusLive, ErrInvalidTransition},
+		{StatusLive, StatusScheduled, ErrInvalidTransition},
+		{StatusCancelled, StatusScheduled, ErrInvalidTransition},
+		{StatusScheduled, "NS", ErrInvalidStatus},
+		{"", StatusScheduled, ErrInvalidStatus},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			err := ValidateTransition(tt.from, tt.to)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
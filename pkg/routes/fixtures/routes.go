@@ -2,10 +2,21 @@ package fixtures
 
 import (
 	"mike/pkg/application"
+	"mike/pkg/auth"
 
 	"github.com/labstack/echo/v4"
 )
 
+// FeedPaths are the routes that take a feed token in the query string, for
+// calendar apps and browser EventSource clients that can't send headers
+var FeedPaths = []string{"/v1/team/:id/fixtures.ics", "/v1/fixtures/stream"}
+
 func RegisterRoutes(e *echo.Echo, app *application.App) {
-	e.POST("/v1/fixtures/daterange", GetFixturesByTimeRange)
+	e.GET("/v1/fixtures", SearchFixtures, auth.RequireScope(auth.ScopeReadFixtures))
+	e.GET("/v1/fixtures/stream", StreamFixtureChanges, auth.RequireScope(auth.ScopeReadFixtures))
+	e.POST("/v1/fixtures/daterange", GetFixturesByTimeRange, auth.RequireScope(auth.ScopeReadFixtures))
+	e.POST("/v1/fixtures/feed-token", CreateFeedToken, auth.RequireScope(auth.ScopeReadFixtures))
+	e.GET("/v1/team/:id/fixtures", GetTeamSchedule, auth.RequireScope(auth.ScopeReadFixtures))
+	e.GET("/v1/team/:id/fixtures.ics", GetTeamFixturesCalendar, auth.RequireScope(auth.ScopeReadFixtures))
+	e.GET("/v1/teams/:id1/vs/:id2", GetHeadToHead, auth.RequireScope(auth.ScopeReadFixtures))
 }
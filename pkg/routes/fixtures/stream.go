@@ -0,0 +1,87 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mike/pkg/application"
+	"mike/pkg/fixturestream"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// heartbeatInterval is how often an idle stream sends a comment, so proxies
+// and load balancers don't close it
+const heartbeatInterval = 15 * time.Second
+
+// retryMillis is how long clients wait before reconnecting to a dropped stream
+const retryMillis = 3000
+
+// StreamFixtureChanges sends fixture changes as Server-Sent Events, one
+// "fixture" event per change, optionally only those of a sport or team. The
+// stream ends when the client goes away, falls too far behind or the server
+// shuts down; EventSource clients reconnect on their own.
+func StreamFixtureChanges(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+
+	var filter fixturestream.Filter
+	var ok bool
+	if filter.SportID, ok = parseIntParam(c, "sport_id", 0); !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid sport_id"})
+	}
+	if filter.TeamID, ok = parseIntParam(c, "team_id", 0); !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid team_id"})
+	}
+
+	rc := http.NewResponseController(c.Response())
+	// Lift the server's WriteTimeout, which would otherwise cut every stream off
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to start stream"})
+	}
+
+	changes, stop := app.FixtureChanges.Subscribe(filter)
+	defer stop()
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, "text/event-stream")
+	header.Set(echo.HeaderCacheControl, "no-cache")
+	header.Set(echo.HeaderConnection, "keep-alive")
+	// Stop nginx buffering the stream
+	header.Set("X-Accel-Buffering", "no")
+	c.Response().WriteHeader(http.StatusOK)
+	if _, err := fmt.Fprintf(c.Response(), "retry: %d\n\n", retryMillis); err != nil {
+		return nil
+	}
+	if err := rc.Flush(); err != nil {
+		return nil
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case change, open := <-changes:
+			if !open {
+				return nil
+			}
+			data, err := json.Marshal(change)
+			if err != nil {
+				return nil
+			}
+			if _, err := fmt.Fprintf(c.Response(), "event: fixture\ndata: %s\n\n", data); err != nil {
+				return nil
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Response(), ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return nil
+		}
+	}
+}
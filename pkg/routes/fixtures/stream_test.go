@@ -0,0 +1,95 @@
+package fixtures
+
+import (
+	"context"
+	"mike/pkg/fixturestream"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// flushRecorder signals every flush, so a test knows when the stream has written
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushed chan struct{}
+}
+
+func (r *flushRecorder) Flush() {
+	r.ResponseRecorder.Flush()
+	r.flushed <- struct{}{}
+}
+
+func Test_StreamFixtureChanges(t *testing.T) {
+	app, e := setupTestApp(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req := httptest.NewRequest(http.MethodGet, "/v1/fixtures/stream?team_id=10", nil).WithContext(ctx)
+	rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder(), flushed: make(chan struct{})}
+	c := e.NewContext(req, rec)
+	c.Set("app", app)
+
+	done := make(chan error)
+	go func() { done <- StreamFixtureChanges(c) }()
+
+	// The stream has subscribed once it flushes its opening retry interval
+	<-rec.flushed
+	app.FixtureChanges.Publish(fixturestream.Change{Op: "update", ID: 1, SportID: 5, TeamID1: 20, TeamID2: 30, Status: "live"})
+	app.FixtureChanges.Publish(fixturestream.Change{Op: "update", ID: 2, SportID: 5, TeamID1: 10, TeamID2: 20, Status: "live"})
+	<-rec.flushed
+	cancel()
+	assert.NoError(t, <-done)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	assert.True(t, strings.HasPrefix(body, "retry: 3000\n\n"))
+	assert.Equal(t, 1, strings.Count(body, "event: fixture\n"), "only the team's fixture is sent")
+	assert.Contains(t, body, `data: {"op":"update","id":2,`)
+}
+
+func Test_StreamFixtureChanges_RejectsInvalidFilters(t *testing.T) {
+	app, e := setupTestApp(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/fixtures/stream?sport_id=abc", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("app", app)
+
+	assert.NoError(t, StreamFixtureChanges(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func Test_StreamFixtureChanges_SubscribesWithFeedToken(t *testing.T) {
+	app, _ := setupTestApp(t)
+	e, token, teardown := setupFeedTestApp(t, app)
+	defer teardown()
+
+	// EventSource can't send headers, so only the URL carries the token
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req := httptest.NewRequest(http.MethodGet, "/v1/fixtures/stream?team_id=10&token="+token, nil).WithContext(ctx)
+	rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder(), flushed: make(chan struct{})}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		e.ServeHTTP(rec, req)
+	}()
+
+	<-rec.flushed
+	app.FixtureChanges.Publish(fixturestream.Change{Op: "update", ID: 2, SportID: 5, TeamID1: 10, TeamID2: 20, Status: "live"})
+	<-rec.flushed
+	cancel()
+	<-done
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `data: {"op":"update","id":2,`)
+
+	unauthorized := httptest.NewRecorder()
+	e.ServeHTTP(unauthorized, httptest.NewRequest(http.MethodGet, "/v1/fixtures/stream", nil))
+	assert.Equal(t, http.StatusUnauthorized, unauthorized.Code, "the token is needed")
+}
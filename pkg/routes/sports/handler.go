@@ -2,6 +2,7 @@ package sports
 
 import (
 	"mike/pkg/application"
+	"mike/pkg/pagination"
 	"mike/pkg/routes/sports/models"
 	"net/http"
 	"strconv"
@@ -42,9 +43,22 @@ func GetSportDetails(c echo.Context) error {
 
 func GetAllSports(c echo.Context) error {
 	app := c.Get("app").(*application.App)
-	sports, err := models.GetAllSports(app.DB)
+	// Clients not asking for pages get every sport as a bare array, as before paging
+	paged := pagination.Paged(c.QueryParam("limit"), c.QueryParam("cursor"))
+	var page pagination.Params
+	if paged {
+		var err error
+		page, err = pagination.ParseParams(c.QueryParam("limit"), c.QueryParam("cursor"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": pagination.ErrorMessage(err)})
+		}
+	}
+	sports, err := models.GetAllSports(app.DB, page)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get sports"})
 	}
+	if !paged {
+		return c.JSON(http.StatusOK, sports.Data)
+	}
 	return c.JSON(http.StatusOK, sports)
 }
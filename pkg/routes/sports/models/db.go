@@ -4,8 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"mike/pkg/pagination"
+	"time"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
 )
 
 type Sport struct {
@@ -15,6 +18,8 @@ type Sport struct {
 	Description   string `bun:"description" json:"description"`
 	ImageURL      string `bun:"image_url" json:"image_url"`
 	IsActive      bool   `bun:"is_active" json:"is_active"`
+	// DateDeleted is set when the sport is deleted, after which it's left out of every query
+	DateDeleted time.Time `bun:"date_deleted,soft_delete,nullzero" json:"-"`
 }
 
 func CheckSportExists(db *bun.DB, sportId int) (bool, error) {
@@ -41,11 +46,82 @@ func GetSportDetails(db *bun.DB, sportId int) (Sport, error) {
 	return sport, nil
 }
 
-func GetAllSports(db *bun.DB) ([]Sport, error) {
+func sportCursor(sport Sport) pagination.Cursor {
+	return pagination.Cursor{ID: sport.ID}
+}
+
+func GetAllSports(db *bun.DB, page pagination.Params) (pagination.Page[Sport], error) {
 	var sports []Sport
-	err := db.NewSelect().Model(&sports).Where("is_active = ?", true).Scan(context.Background())
+	q := db.NewSelect().Model(&sports).Where("is_active = ?", true)
+	if page.Cursor != nil {
+		q = q.Where("id > ?", page.Cursor.ID)
+	}
+	q = q.OrderExpr("id ASC")
+	if page.Limit > 0 {
+		q = q.Limit(page.Limit + 1)
+	}
+	if err := q.Scan(context.Background()); err != nil {
+		return pagination.Page[Sport]{}, err
+	}
+	return pagination.NewPage(sports, page.Limit, sportCursor), nil
+}
+
+// isUniqueViolation reports whether err is Postgres rejecting a duplicate
+func isUniqueViolation(err error) bool {
+	var pgErr pgdriver.Error
+	return errors.As(err, &pgErr) && pgErr.Field('C') == "23505"
+}
+
+func CreateSport(db *bun.DB, sport *Sport) error {
+	_, err := db.NewInsert().Model(sport).Returning("*").Exec(context.Background())
+	if isUniqueViolation(err) {
+		return errors.New("sport already exists")
+	}
+	return err
+}
+
+// UpdateSport saves sport's name, description, image and whether it's active
+func UpdateSport(db *bun.DB, sport Sport) (Sport, error) {
+	err := db.NewUpdate().
+		Model(&sport).
+		Set("name = ?name").
+		Set("description = ?description").
+		Set("image_url = ?image_url").
+		Set("is_active = ?is_active").
+		Set("date_updated = current_timestamp").
+		WherePK().
+		Returning("*").
+		Scan(context.Background())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Sport{}, errors.New("sport not found")
+		}
+		if isUniqueViolation(err) {
+			return Sport{}, errors.New("sport already exists")
+		}
+		return Sport{}, err
+	}
+	return sport, nil
+}
+
+// DeleteSport soft deletes a sport, as long as none of its teams are left
+func DeleteSport(db *bun.DB, sportId int) error {
+	ctx := context.Background()
+	hasTeams, err := db.NewSelect().Table("teams").Where("sport_id = ? AND deleted_at IS NULL", sportId).Exists(ctx)
+	if err != nil {
+		return err
+	}
+	if hasTeams {
+		return errors.New("sport has teams")
+	}
+	res, err := db.NewDelete().Model((*Sport)(nil)).Where("id = ?", sportId).Exec(ctx)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return errors.New("sport not found")
 	}
-	return sports, nil
+	return nil
 }
@@ -2,12 +2,13 @@ package sports
 
 import (
 	"mike/pkg/application"
+	"mike/pkg/auth"
 
 	"github.com/labstack/echo/v4"
 )
 
 func RegisterRoutes(e *echo.Echo, app *application.App) {
-	e.GET("/v1/sport/exists/:id", CheckSportExists)
-	e.GET("/v1/sport/details/:id", GetSportDetails)
-	e.GET("/v1/sports", GetAllSports)
+	e.GET("/v1/sport/exists/:id", CheckSportExists, auth.RequireScope(auth.ScopeReadSports))
+	e.GET("/v1/sport/details/:id", GetSportDetails, auth.RequireScope(auth.ScopeReadSports))
+	e.GET("/v1/sports", GetAllSports, auth.RequireScope(auth.ScopeReadSports))
 }
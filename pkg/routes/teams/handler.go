@@ -2,13 +2,50 @@ package teams
 
 import (
 	"mike/pkg/application"
+	"mike/pkg/pagination"
 	"mike/pkg/routes/teams/models"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 )
 
+// maxQueryLength bounds the name search, which gets slow on long patterns
+const maxQueryLength = 100
+
+func SearchTeams(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	var filter models.TeamFilter
+	if raw := c.QueryParam("sport_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid sport_id"})
+		}
+		filter.SportID = id
+	}
+	filter.Query = strings.TrimSpace(c.QueryParam("q"))
+	if len(filter.Query) > maxQueryLength {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Search query too long"})
+	}
+	if raw := c.QueryParam("active"); raw != "" {
+		active, err := strconv.ParseBool(raw)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid active"})
+		}
+		filter.Active = &active
+	}
+	page, err := pagination.ParseParams(c.QueryParam("limit"), c.QueryParam("cursor"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": pagination.ErrorMessage(err)})
+	}
+	teams, err := models.SearchTeams(app.DB, filter, page)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to search teams"})
+	}
+	return c.JSON(http.StatusOK, teams)
+}
+
 func CheckTeamExists(c echo.Context) error {
 	app := c.Get("app").(*application.App)
 	teamId := c.Param("id")
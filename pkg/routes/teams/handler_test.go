@@ -0,0 +1,193 @@
+package teams
+
+import (
+	"context"
+	"encoding/json"
+	"mike/config"
+	"mike/pkg/application"
+	"mike/pkg/auth"
+	"mike/pkg/pagination"
+	"mike/pkg/routes/teams/models"
+	"mike/utils"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+func setupTestApp(t *testing.T) (*application.App, *echo.Echo) {
+	cfg := config.GetConfig()
+	app, err := application.New(cfg)
+	assert.NoError(t, err, "Failed to create application")
+	app.DB = utils.GetDatabase()
+
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("app", app)
+			auth.SetKey(c, auth.APIKey{Scopes: []string{auth.ScopeReadTeams}})
+			return next(c)
+		}
+	})
+	RegisterRoutes(e, app)
+	return app, e
+}
+
+// setupTestData creates a sport with an active and an inactive team and one
+// team in another sport, returning the sport ids and a cleanup function
+func setupTestData(t *testing.T, db *bun.DB) ([2]int, func()) {
+	truncate := func() {
+		_, _ = db.NewRaw("TRUNCATE TABLE teams CASCADE").Exec(context.Background())
+		_, _ = db.NewRaw("TRUNCATE TABLE sports CASCADE").Exec(context.Background())
+	}
+	truncate()
+
+	var sportIDs [2]int
+	for i, name := range []string{"Test Sport Handler", "Other Sport Handler"} {
+		sport := struct {
+			bun.BaseModel `bun:"sports"`
+			ID            int    `bun:"id,pk,autoincrement"`
+			Name          string `bun:"name"`
+			IsActive      bool   `bun:"is_active"`
+		}{Name: name, IsActive: true}
+		_, err := db.NewInsert().Model(&sport).Returning("id").Exec(context.Background())
+		assert.NoError(t, err, "Error inserting sport")
+		sportIDs[i] = sport.ID
+	}
+
+	teams := []struct {
+		bun.BaseModel `bun:"teams"`
+		Name          string `bun:"name"`
+		SportId       int    `bun:"sport_id"`
+		IsActive      bool   `bun:"is_active"`
+	}{
+		{Name: "Club Atlético Handler", SportId: sportIDs[0], IsActive: true},
+		{Name: "Atletico_Reserves Handler", SportId: sportIDs[0], IsActive: false},
+		{Name: "Atletico Handler", SportId: sportIDs[1], IsActive: true},
+	}
+	for _, team := range teams {
+		_, err := db.NewInsert().Model(&team).Exec(context.Background())
+		assert.NoError(t, err, "Error inserting team")
+	}
+	return sportIDs, truncate
+}
+
+// searchTeams requests one page of GET /v1/teams with query
+func searchTeams(e *echo.Echo, query url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/v1/teams?"+query.Encode(), nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func Test_SearchTeams(t *testing.T) {
+	app, e := setupTestApp(t)
+	sportIDs, teardown := setupTestData(t, app.DB)
+	defer teardown()
+
+	tests := []struct {
+		name     string
+		query    url.Values
+		expected []string
+	}{
+		{
+			name:     "every team",
+			query:    url.Values{},
+			expected: []string{"Club Atlético Handler", "Atletico_Reserves Handler", "Atletico Handler"},
+		},
+		{
+			name:     "accents and case ignored",
+			query:    url.Values{"q": {"  ATLETICO  "}},
+			expected: []string{"Club Atlético Handler", "Atletico_Reserves Handler", "Atletico Handler"},
+		},
+		{
+			name:     "underscore is literal",
+			query:    url.Values{"q": {"atletico_"}},
+			expected: []string{"Atletico_Reserves Handler"},
+		},
+		{
+			name:     "by sport",
+			query:    url.Values{"sport_id": {strconv.Itoa(sportIDs[1])}},
+			expected: []string{"Atletico Handler"},
+		},
+		{
+			name:     "active only",
+			query:    url.Values{"sport_id": {strconv.Itoa(sportIDs[0])}, "active": {"true"}},
+			expected: []string{"Club Atlético Handler"},
+		},
+		{
+			name:     "inactive only",
+			query:    url.Values{"active": {"false"}},
+			expected: []string{"Atletico_Reserves Handler"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := searchTeams(e, test.query)
+			assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			var page pagination.Page[models.Team]
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+			names := []string{}
+			for _, team := range page.Data {
+				names = append(names, team.Name)
+			}
+			assert.Equal(t, test.expected, names)
+			assert.Empty(t, page.NextCursor)
+		})
+	}
+}
+
+func Test_SearchTeams_Paging(t *testing.T) {
+	app, e := setupTestApp(t)
+	_, teardown := setupTestData(t, app.DB)
+	defer teardown()
+
+	rec := searchTeams(e, url.Values{"limit": {"2"}})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var first pagination.Page[models.Team]
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &first))
+	assert.Len(t, first.Data, 2)
+	assert.NotEmpty(t, first.NextCursor)
+
+	rec = searchTeams(e, url.Values{"limit": {"2"}, "cursor": {first.NextCursor}})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var second pagination.Page[models.Team]
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &second))
+	if assert.Len(t, second.Data, 1) {
+		assert.Equal(t, "Atletico Handler", second.Data[0].Name)
+		assert.Greater(t, second.Data[0].ID, first.Data[1].ID)
+	}
+	assert.Empty(t, second.NextCursor)
+}
+
+func Test_SearchTeams_InvalidParams(t *testing.T) {
+	_, e := setupTestApp(t)
+
+	tests := []struct {
+		name          string
+		query         url.Values
+		expectedError string
+	}{
+		{name: "sport_id not a number", query: url.Values{"sport_id": {"abc"}}, expectedError: "Invalid sport_id"},
+		{name: "sport_id not positive", query: url.Values{"sport_id": {"0"}}, expectedError: "Invalid sport_id"},
+		{name: "active not a bool", query: url.Values{"active": {"maybe"}}, expectedError: "Invalid active"},
+		{name: "query too long", query: url.Values{"q": {strings.Repeat("a", maxQueryLength+1)}}, expectedError: "Search query too long"},
+		{name: "invalid limit", query: url.Values{"limit": {"0"}}, expectedError: "Invalid limit, must be between 1 and 200"},
+		{name: "invalid cursor", query: url.Values{"cursor": {"not-a-cursor"}}, expectedError: "Invalid cursor"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := searchTeams(e, test.query)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			var response map[string]string
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, test.expectedError, response["error"])
+		})
+	}
+}
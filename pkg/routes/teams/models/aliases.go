@@ -0,0 +1,248 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"mike/pkg/pagination"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Review statuses
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// TeamAlias is another name, or a provider's id, that a team is known by
+type TeamAlias struct {
+	bun.BaseModel `bun:"team_aliases,alias:team_alias"`
+	ID            int    `bun:"id,pk,autoincrement" json:"id"`
+	TeamID        int    `bun:"team_id" json:"team_id"`
+	SportID       int    `bun:"sport_id" json:"sport_id"`
+	Provider      string `bun:"provider" json:"provider"`
+	// ExternalID is the provider's id for the team; name-only aliases leave it empty
+	ExternalID string    `bun:"external_id,nullzero" json:"external_id,omitempty"`
+	Name       string    `bun:"name" json:"name"`
+	CreatedAt  time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
+}
+
+// AliasReview is a provider team that only resembled a stored team, queued for
+// someone to approve or reject the match
+type AliasReview struct {
+	bun.BaseModel   `bun:"team_alias_reviews,alias:review"`
+	ID              int        `bun:"id,pk,autoincrement" json:"id"`
+	SportID         int        `bun:"sport_id" json:"sport_id"`
+	Provider        string     `bun:"provider" json:"provider"`
+	ExternalID      string     `bun:"external_id,nullzero" json:"external_id,omitempty"`
+	Name            string     `bun:"name" json:"name"`
+	CandidateTeamID int        `bun:"candidate_team_id,nullzero" json:"candidate_team_id,omitempty"`
+	Similarity      float64    `bun:"similarity" json:"similarity"`
+	Status          string     `bun:"status" json:"status"`
+	TeamID          int        `bun:"team_id,nullzero" json:"team_id,omitempty"`
+	CreatedAt       time.Time  `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
+	ResolvedAt      *time.Time `bun:"resolved_at" json:"resolved_at,omitempty"`
+}
+
+// GetTeamByAlias returns the team a provider's team id is an alias of
+func GetTeamByAlias(ctx context.Context, db bun.IDB, sportID int, provider, externalID string) (Team, error) {
+	var team Team
+	err := db.NewSelect().
+		Model(&team).
+		Join("JOIN team_aliases AS team_alias ON team_alias.team_id = team.id").
+		Where("team_alias.sport_id = ? AND team_alias.provider = ? AND team_alias.external_id = ?", sportID, provider, externalID).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Team{}, errors.New("team not found")
+		}
+		return Team{}, err
+	}
+	return team, nil
+}
+
+// GetTeamsByNormalizedName returns the sport's teams whose name, or any alias,
+// matches name once both are normalized with team_name_normalize
+func GetTeamsByNormalizedName(ctx context.Context, db bun.IDB, sportID int, name string) ([]Team, error) {
+	var teams []Team
+	err := db.NewSelect().
+		Model(&teams).
+		Where("team.sport_id = ?", sportID).
+		Where("team_name_normalize(?) <> ''", name).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				Where("team_name_normalize(team.name) = team_name_normalize(?)", name).
+				WhereOr("team.id IN (SELECT team_id FROM team_aliases WHERE sport_id = ? AND normalized_name = team_name_normalize(?))", sportID, name)
+		}).
+		OrderExpr("team.id ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+// FindSimilarTeam returns the sport's team whose name is most similar to name
+// by trigram similarity, if any reaches minSimilarity. Teams the provider
+// already knows by another id are left out: a provider lists each team once.
+func FindSimilarTeam(ctx context.Context, db bun.IDB, sportID int, provider, name string, minSimilarity float64) (Team, float64, error) {
+	var match struct {
+		Team       `bun:",extend"`
+		Similarity float64 `bun:"similarity"`
+	}
+	err := db.NewSelect().
+		Model(&match).
+		ColumnExpr("team.*").
+		ColumnExpr("similarity(search_normalize(team.name), search_normalize(?)) AS similarity", name).
+		Where("team.sport_id = ?", sportID).
+		Where("similarity(search_normalize(team.name), search_normalize(?)) >= ?", name, minSimilarity).
+		Where("NOT EXISTS (SELECT 1 FROM team_aliases WHERE team_id = team.id AND provider = ? AND external_id IS NOT NULL)", provider).
+		OrderExpr("similarity DESC, team.id ASC").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Team{}, 0, errors.New("team not found")
+		}
+		return Team{}, 0, err
+	}
+	return match.Team, match.Similarity, nil
+}
+
+// SaveAlias records alias, leaving an existing alias for the same provider id
+// or name as it is. It reports whether alias was new.
+func SaveAlias(ctx context.Context, db bun.IDB, alias *TeamAlias) (bool, error) {
+	res, err := db.NewInsert().
+		Model(alias).
+		On("CONFLICT DO NOTHING").
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	inserted, err := res.RowsAffected()
+	return inserted > 0, err
+}
+
+// GetAliases returns the aliases of a team
+func GetAliases(db *bun.DB, teamID int) ([]TeamAlias, error) {
+	aliases := []TeamAlias{}
+	err := db.NewSelect().
+		Model(&aliases).
+		Where("team_id = ?", teamID).
+		OrderExpr("id ASC").
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return aliases, nil
+}
+
+// QueueAliasReview adds review to the queue unless the provider's name is already in it
+func QueueAliasReview(ctx context.Context, db bun.IDB, review AliasReview) error {
+	review.Status = ReviewPending
+	_, err := db.NewInsert().
+		Model(&review).
+		On("CONFLICT (sport_id, provider, name) DO NOTHING").
+		Exec(ctx)
+	return err
+}
+
+func GetAliasReview(db *bun.DB, reviewID int) (AliasReview, error) {
+	var review AliasReview
+	err := db.NewSelect().Model(&review).Where("id = ?", reviewID).Scan(context.Background())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return AliasReview{}, errors.New("review not found")
+		}
+		return AliasReview{}, err
+	}
+	return review, nil
+}
+
+// GetAliasReviewByName returns the review of a provider's team name
+func GetAliasReviewByName(ctx context.Context, db bun.IDB, sportID int, provider, name string) (AliasReview, error) {
+	var review AliasReview
+	err := db.NewSelect().
+		Model(&review).
+		Where("sport_id = ? AND provider = ? AND name = ?", sportID, provider, name).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return AliasReview{}, errors.New("review not found")
+		}
+		return AliasReview{}, err
+	}
+	return review, nil
+}
+
+func reviewCursor(review AliasReview) pagination.Cursor {
+	return pagination.Cursor{ID: review.ID}
+}
+
+// ListAliasReviews lists reviews oldest first, optionally only those with status
+func ListAliasReviews(db *bun.DB, status string, page pagination.Params) (pagination.Page[AliasReview], error) {
+	var reviews []AliasReview
+	q := db.NewSelect().Model(&reviews)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if page.Cursor != nil {
+		q = q.Where("id > ?", page.Cursor.ID)
+	}
+	err := q.OrderExpr("id ASC").Limit(page.Limit + 1).Scan(context.Background())
+	if err != nil {
+		return pagination.Page[AliasReview]{}, err
+	}
+	return pagination.NewPage(reviews, page.Limit, reviewCursor), nil
+}
+
+// ApproveAliasReview resolves a pending review by making its name, and
+// provider id if it had one, aliases of teamID
+func ApproveAliasReview(db *bun.DB, reviewID, teamID int) (AliasReview, error) {
+	var review AliasReview
+	err := db.RunInTx(context.Background(), nil, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		review, err = resolveReview(ctx, tx, reviewID, ReviewApproved, teamID)
+		if err != nil {
+			return err
+		}
+		alias := TeamAlias{TeamID: teamID, SportID: review.SportID, Provider: review.Provider, Name: review.Name}
+		if _, err := SaveAlias(ctx, tx, &alias); err != nil {
+			return err
+		}
+		if review.ExternalID != "" {
+			idAlias := TeamAlias{TeamID: teamID, SportID: review.SportID, Provider: review.Provider, ExternalID: review.ExternalID, Name: review.Name}
+			_, err = SaveAlias(ctx, tx, &idAlias)
+		}
+		return err
+	})
+	return review, err
+}
+
+// RejectAliasReview resolves a pending review as not being the candidate team,
+// so the next sync of the provider's teams adds it as a team of its own
+func RejectAliasReview(db *bun.DB, reviewID int) (AliasReview, error) {
+	return resolveReview(context.Background(), db, reviewID, ReviewRejected, 0)
+}
+
+func resolveReview(ctx context.Context, db bun.IDB, reviewID int, status string, teamID int) (AliasReview, error) {
+	var review AliasReview
+	err := db.NewUpdate().
+		Model(&review).
+		Set("status = ?", status).
+		Set("team_id = NULLIF(?, 0)", teamID).
+		Set("resolved_at = current_timestamp").
+		Where("id = ? AND status = ?", reviewID, ReviewPending).
+		Returning("*").
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return AliasReview{}, errors.New("review not found")
+		}
+		return AliasReview{}, err
+	}
+	return review, nil
+}
@@ -5,8 +5,13 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"mike/pkg/pagination"
+	venueModels "mike/pkg/routes/venues/models"
+	"strings"
+	"time"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
 )
 
 type Team struct {
@@ -17,7 +22,14 @@ type Team struct {
 	Description   string `bun:"description" json:"description"`
 	ImageURL      string `bun:"image_url" json:"image_url"`
 	IsActive      bool   `bun:"is_active" json:"is_active"`
-	ApiID         int    `bun:"api_id" json:"api_id"`
+	// ApiID is the id the team was first imported with; team_aliases holds
+	// every provider's id for it
+	ApiID int `bun:"api_id" json:"api_id"`
+	// VenueID is the team's home venue
+	VenueID int                `bun:"venue_id,nullzero" json:"venue_id,omitempty"`
+	Venue   *venueModels.Venue `bun:"rel:belongs-to,join:venue_id=id" json:"venue,omitempty"`
+	// DeletedAt is set when the team is deleted, after which it's left out of every query
+	DeletedAt time.Time `bun:"deleted_at,soft_delete,nullzero" json:"-"`
 }
 
 func CheckTeamExists(db *bun.DB, teamId int) (bool, error) {
@@ -34,7 +46,7 @@ func CheckTeamExists(db *bun.DB, teamId int) (bool, error) {
 
 func GetTeamDetails(db *bun.DB, teamId int) (Team, error) {
 	var team Team
-	err := db.NewSelect().Model(&team).Where("id = ?", teamId).Scan(context.Background())
+	err := db.NewSelect().Model(&team).Relation("Venue").Where("team.id = ?", teamId).Scan(context.Background())
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return Team{}, errors.New("team not found")
@@ -43,3 +55,163 @@ func GetTeamDetails(db *bun.DB, teamId int) (Team, error) {
 	}
 	return team, nil
 }
+
+// TeamFilter narrows a team listing; zero values don't filter
+type TeamFilter struct {
+	SportID int
+	// Query matches anywhere in the name, ignoring case and accents
+	Query  string
+	Active *bool
+}
+
+func teamCursor(team Team) pagination.Cursor {
+	return pagination.Cursor{ID: team.ID}
+}
+
+// likeEscaper escapes LIKE wildcards so a query only matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// SearchTeams lists teams matching filter in id order. The name match uses
+// search_normalize, which idx_teams_name_search indexes with trigrams.
+func SearchTeams(db *bun.DB, filter TeamFilter, page pagination.Params) (pagination.Page[Team], error) {
+	var teams []Team
+	q := db.NewSelect().Model(&teams)
+	if filter.SportID != 0 {
+		q = q.Where("sport_id = ?", filter.SportID)
+	}
+	if filter.Query != "" {
+		q = q.Where("search_normalize(name) LIKE '%' || search_normalize(?) || '%'", likeEscaper.Replace(filter.Query))
+	}
+	if filter.Active != nil {
+		q = q.Where("is_active = ?", *filter.Active)
+	}
+	if page.Cursor != nil {
+		q = q.Where("id > ?", page.Cursor.ID)
+	}
+	err := q.OrderExpr("id ASC").Limit(page.Limit + 1).Scan(context.Background())
+	if err != nil {
+		return pagination.Page[Team]{}, err
+	}
+	return pagination.NewPage(teams, page.Limit, teamCursor), nil
+}
+
+// GetTeamsByIDs returns the teams with the given ids, keyed by id. Ids with no
+// team are left out.
+func GetTeamsByIDs(db *bun.DB, teamIds []int) (map[int]Team, error) {
+	teams := make(map[int]Team, len(teamIds))
+	if len(teamIds) == 0 {
+		return teams, nil
+	}
+	var rows []Team
+	err := db.NewSelect().Model(&rows).Where("id IN (?)", bun.In(teamIds)).Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	for _, team := range rows {
+		teams[team.ID] = team
+	}
+	return teams, nil
+}
+
+// InsertTeams saves teams that don't exist yet and returns how many were inserted
+func InsertTeams(db *bun.DB, teams []Team) (int, error) {
+	if len(teams) == 0 {
+		return 0, nil
+	}
+
+	res, err := db.NewInsert().
+		Model(&teams).
+		// Matches the partial unique index, so deleted teams don't count
+		On("CONFLICT (name, sport_id) WHERE deleted_at IS NULL DO NOTHING").
+		Exec(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	inserted, err := res.RowsAffected()
+	return int(inserted), err
+}
+
+// SetTeamVenues sets the home venue of teams, keyed by team id
+func SetTeamVenues(ctx context.Context, db bun.IDB, venueByTeamID map[int]int) error {
+	for teamID, venueID := range venueByTeamID {
+		_, err := db.NewUpdate().
+			Model((*Team)(nil)).
+			Set("venue_id = ?", venueID).
+			Where("id = ?", teamID).
+			Where("venue_id IS DISTINCT FROM ?", venueID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether err is Postgres rejecting a duplicate
+func isUniqueViolation(err error) bool {
+	var pgErr pgdriver.Error
+	return errors.As(err, &pgErr) && pgErr.Field('C') == "23505"
+}
+
+func CreateTeam(db *bun.DB, team *Team) error {
+	_, err := db.NewInsert().Model(team).Returning("*").Exec(context.Background())
+	if isUniqueViolation(err) {
+		return errors.New("team already exists")
+	}
+	return err
+}
+
+// UpdateTeam saves team's name, sport, description, image, venue and whether it's active
+func UpdateTeam(db *bun.DB, team Team) (Team, error) {
+	err := db.NewUpdate().
+		Model(&team).
+		Set("name = ?name").
+		Set("sport_id = ?sport_id").
+		Set("description = ?description").
+		Set("image_url = ?image_url").
+		Set("is_active = ?is_active").
+		Set("venue_id = ?venue_id").
+		Set("updated_at = current_timestamp").
+		WherePK().
+		Returning("*").
+		Scan(context.Background())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Team{}, errors.New("team not found")
+		}
+		if isUniqueViolation(err) {
+			return Team{}, errors.New("team already exists")
+		}
+		return Team{}, err
+	}
+	return team, nil
+}
+
+// HasFixtures reports whether a team plays in any fixture that isn't deleted
+func HasFixtures(db *bun.DB, teamId int) (bool, error) {
+	return db.NewSelect().
+		Table("fixtures").
+		Where("(team_id_1 = ? OR team_id_2 = ?) AND deleted_at IS NULL", teamId, teamId).
+		Exists(context.Background())
+}
+
+// DeleteTeam soft deletes a team, as long as it isn't in any fixture
+func DeleteTeam(db *bun.DB, teamId int) error {
+	hasFixtures, err := HasFixtures(db, teamId)
+	if err != nil {
+		return err
+	}
+	if hasFixtures {
+		return errors.New("team has fixtures")
+	}
+	res, err := db.NewDelete().Model((*Team)(nil)).Where("id = ?", teamId).Exec(context.Background())
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return errors.New("team not found")
+	}
+	return nil
+}
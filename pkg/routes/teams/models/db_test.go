@@ -0,0 +1,177 @@
+package models
+
+import (
+	"context"
+	"mike/pkg/pagination"
+	"mike/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+// setupSearchTestData creates two sports and their teams, returning the sport
+// ids, the team ids by name and a cleanup function
+func setupSearchTestData(t *testing.T, db *bun.DB) ([2]int, map[string]int, func()) {
+	truncate := func() {
+		_, _ = db.NewRaw("TRUNCATE TABLE teams CASCADE").Exec(context.Background())
+		_, _ = db.NewRaw("TRUNCATE TABLE sports CASCADE").Exec(context.Background())
+	}
+	truncate()
+
+	var sportIDs [2]int
+	for i, name := range []string{"Test Sport Search", "Other Sport Search"} {
+		sport := struct {
+			bun.BaseModel `bun:"sports"`
+			ID            int    `bun:"id,pk,autoincrement"`
+			Name          string `bun:"name"`
+			IsActive      bool   `bun:"is_active"`
+		}{Name: name, IsActive: true}
+		_, err := db.NewInsert().Model(&sport).Returning("id").Exec(context.Background())
+		assert.NoError(t, err)
+		sportIDs[i] = sport.ID
+	}
+
+	teams := []struct {
+		name    string
+		sportID int
+		active  bool
+	}{
+		{"Atlético Madrid", sportIDs[0], true},
+		{"ATLETICO Nacional", sportIDs[0], true},
+		{"Athletic Club", sportIDs[0], true},
+		{"100% Fans", sportIDs[0], true},
+		{"1000 Fans", sportIDs[0], true},
+		{"Real_Betis", sportIDs[0], true},
+		{"Real Betis", sportIDs[0], false},
+		{"Atletico Ottawa", sportIDs[1], true},
+	}
+	teamIDs := make(map[string]int, len(teams))
+	for _, team := range teams {
+		row := struct {
+			bun.BaseModel `bun:"teams"`
+			ID            int    `bun:"id,pk,autoincrement"`
+			Name          string `bun:"name"`
+			SportId       int    `bun:"sport_id"`
+			IsActive      bool   `bun:"is_active"`
+		}{Name: team.name, SportId: team.sportID, IsActive: team.active}
+		_, err := db.NewInsert().Model(&row).Returning("id").Exec(context.Background())
+		assert.NoError(t, err)
+		teamIDs[team.name] = row.ID
+	}
+	return sportIDs, teamIDs, truncate
+}
+
+// names returns the names of teams, in order
+func names(teams []Team) []string {
+	names := make([]string, 0, len(teams))
+	for _, team := range teams {
+		names = append(names, team.Name)
+	}
+	return names
+}
+
+func Test_SearchTeams(t *testing.T) {
+	db := utils.GetDatabase()
+	sportIDs, _, teardown := setupSearchTestData(t, db)
+	defer teardown()
+
+	active, inactive := true, false
+	tests := []struct {
+		name     string
+		filter   TeamFilter
+		expected []string
+	}{
+		{
+			name:     "ignores accents and case",
+			filter:   TeamFilter{Query: "atlético"},
+			expected: []string{"Atlético Madrid", "ATLETICO Nacional", "Atletico Ottawa"},
+		},
+		{
+			name:     "unaccented query matches accented names",
+			filter:   TeamFilter{Query: "ATLETICO M"},
+			expected: []string{"Atlético Madrid"},
+		},
+		{
+			name:     "percent matches literally",
+			filter:   TeamFilter{Query: "0%"},
+			expected: []string{"100% Fans"},
+		},
+		{
+			name:     "underscore matches literally",
+			filter:   TeamFilter{Query: "real_"},
+			expected: []string{"Real_Betis"},
+		},
+		{
+			name:     "sport filter",
+			filter:   TeamFilter{SportID: sportIDs[1]},
+			expected: []string{"Atletico Ottawa"},
+		},
+		{
+			name:     "sport filter with a query",
+			filter:   TeamFilter{SportID: sportIDs[0], Query: "atletico"},
+			expected: []string{"Atlético Madrid", "ATLETICO Nacional"},
+		},
+		{
+			name:     "active teams",
+			filter:   TeamFilter{Query: "betis", Active: &active},
+			expected: []string{"Real_Betis"},
+		},
+		{
+			name:     "inactive teams",
+			filter:   TeamFilter{Active: &inactive},
+			expected: []string{"Real Betis"},
+		},
+		{
+			name:     "no match",
+			filter:   TeamFilter{Query: "united"},
+			expected: []string{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			page, err := SearchTeams(db, test.filter, pagination.Params{Limit: pagination.DefaultLimit})
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, names(page.Data))
+			assert.Empty(t, page.NextCursor)
+		})
+	}
+}
+
+func Test_SearchTeams_Paging(t *testing.T) {
+	db := utils.GetDatabase()
+	sportIDs, _, teardown := setupSearchTestData(t, db)
+	defer teardown()
+
+	filter := TeamFilter{SportID: sportIDs[0]}
+	var seen []string
+	params := pagination.Params{Limit: 3}
+	for pages := 0; ; pages++ {
+		assert.Less(t, pages, 3, "too many pages")
+		page, err := SearchTeams(db, filter, params)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, len(page.Data), 3)
+		seen = append(seen, names(page.Data)...)
+		if page.NextCursor == "" {
+			break
+		}
+		params, err = pagination.ParseParams("3", page.NextCursor)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, []string{
+		"Atlético Madrid", "ATLETICO Nacional", "Athletic Club",
+		"100% Fans", "1000 Fans", "Real_Betis",
+		"Real Betis",
+	}, seen)
+}
+
+func Test_SearchTeams_LeavesOutDeletedTeams(t *testing.T) {
+	db := utils.GetDatabase()
+	_, teamIDs, teardown := setupSearchTestData(t, db)
+	defer teardown()
+
+	assert.NoError(t, DeleteTeam(db, teamIDs["Atlético Madrid"]))
+	page, err := SearchTeams(db, TeamFilter{Query: "madrid"}, pagination.Params{Limit: pagination.DefaultLimit})
+	assert.NoError(t, err)
+	assert.Empty(t, page.Data)
+}
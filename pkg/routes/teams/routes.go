@@ -2,11 +2,13 @@ package teams
 
 import (
 	"mike/pkg/application"
+	"mike/pkg/auth"
 
 	"github.com/labstack/echo/v4"
 )
 
 func RegisterRoutes(e *echo.Echo, app *application.App) {
-	e.GET("/v1/team/exists/:id", CheckTeamExists)
-	e.GET("/v1/team/details/:id", GetTeamDetails)
+	e.GET("/v1/teams", SearchTeams, auth.RequireScope(auth.ScopeReadTeams))
+	e.GET("/v1/team/exists/:id", CheckTeamExists, auth.RequireScope(auth.ScopeReadTeams))
+	e.GET("/v1/team/details/:id", GetTeamDetails, auth.RequireScope(auth.ScopeReadTeams))
 }
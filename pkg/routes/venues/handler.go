@@ -0,0 +1,26 @@
+package venues
+
+import (
+	"mike/pkg/application"
+	"mike/pkg/routes/venues/models"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func GetVenueDetails(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	venueID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid venue ID"})
+	}
+	venue, err := models.GetVenueDetails(app.DB, venueID)
+	if err != nil {
+		if err.Error() == "venue not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Venue not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get venue details"})
+	}
+	return c.JSON(http.StatusOK, venue)
+}
@@ -0,0 +1,191 @@
+package venues
+
+import (
+	"context"
+	"encoding/json"
+	"mike/config"
+	"mike/pkg/application"
+	"mike/pkg/auth"
+	"mike/pkg/routes/fixtures"
+	fixtureModels "mike/pkg/routes/fixtures/models"
+	"mike/pkg/routes/teams"
+	teamModels "mike/pkg/routes/teams/models"
+	"mike/pkg/routes/venues/models"
+	"mike/utils"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+// setupTestApp serves the venue routes and the team and fixture routes that embed venues
+func setupTestApp(t *testing.T) (*application.App, *echo.Echo) {
+	cfg := config.GetConfig()
+	app, err := application.New(cfg)
+	assert.NoError(t, err, "Failed to create application")
+	app.DB = utils.GetDatabase()
+
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("app", app)
+			auth.SetKey(c, auth.APIKey{Scopes: []string{auth.ScopeReadVenues, auth.ScopeReadTeams, auth.ScopeReadFixtures}})
+			return next(c)
+		}
+	})
+	RegisterRoutes(e, app)
+	teams.RegisterRoutes(e, app)
+	fixtures.RegisterRoutes(e, app)
+	return app, e
+}
+
+// testData is what setupTestData creates: a venue, a home team playing there
+// and an away team without one, and a fixture at the venue
+type testData struct {
+	venue     models.Venue
+	sportID   int
+	homeID    int
+	awayID    int
+	fixtureID int
+}
+
+// setupTestData creates the test data and returns it with a cleanup function
+func setupTestData(t *testing.T, db *bun.DB) (testData, func()) {
+	ctx := context.Background()
+	truncate := func() {
+		for _, table := range []string{"fixtures", "teams", "sports", "venues"} {
+			_, _ = db.NewRaw("TRUNCATE TABLE " + table + " CASCADE").Exec(ctx)
+		}
+	}
+	truncate()
+
+	data := testData{venue: models.Venue{
+		Name:     "Test Stadium Handler",
+		Address:  "1 Stadium Way",
+		City:     "Testville",
+		Capacity: 40000,
+		Surface:  "grass",
+	}}
+	_, err := db.NewInsert().Model(&data.venue).Returning("id").Exec(ctx)
+	assert.NoError(t, err, "Error inserting venue")
+
+	sport := struct {
+		bun.BaseModel `bun:"sports"`
+		ID            int    `bun:"id,pk,autoincrement"`
+		Name          string `bun:"name"`
+		IsActive      bool   `bun:"is_active"`
+	}{Name: "Test Sport Handler", IsActive: true}
+	_, err = db.NewInsert().Model(&sport).Returning("id").Exec(ctx)
+	assert.NoError(t, err, "Error inserting sport")
+	data.sportID = sport.ID
+
+	for _, team := range []struct {
+		name    string
+		venueID int
+		id      *int
+	}{
+		{"Home Team Handler", data.venue.ID, &data.homeID},
+		{"Away Team Handler", 0, &data.awayID},
+	} {
+		row := teamModels.Team{Name: team.name, SportId: sport.ID, IsActive: true, VenueID: team.venueID}
+		_, err := db.NewInsert().Model(&row).Returning("id").Exec(ctx)
+		assert.NoError(t, err, "Error inserting team")
+		*team.id = row.ID
+	}
+
+	fixture := fixtureModels.Fixture{
+		SportID: sport.ID, TeamID1: data.homeID, TeamID2: data.awayID, VenueID: data.venue.ID,
+		DateTime: time.Date(2025, 8, 16, 14, 0, 0, 0, time.UTC),
+		Status:   fixtureModels.StatusScheduled,
+	}
+	_, err = db.NewInsert().Model(&fixture).Returning("id").Exec(ctx)
+	assert.NoError(t, err, "Error inserting fixture")
+	data.fixtureID = fixture.ID
+	return data, truncate
+}
+
+// get requests path and returns the response
+func get(e *echo.Echo, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func Test_GetVenueDetails(t *testing.T) {
+	app, e := setupTestApp(t)
+	data, teardown := setupTestData(t, app.DB)
+	defer teardown()
+
+	rec := get(e, "/v1/venue/"+strconv.Itoa(data.venue.ID))
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var venue models.Venue
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &venue))
+	assert.Equal(t, data.venue, venue)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{name: "unknown venue", path: "/v1/venue/999999", expectedStatus: http.StatusNotFound, expectedError: "Venue not found"},
+		{name: "invalid id", path: "/v1/venue/abc", expectedStatus: http.StatusBadRequest, expectedError: "Invalid venue ID"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := get(e, test.path)
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			var response map[string]string
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, test.expectedError, response["error"])
+		})
+	}
+}
+
+func Test_VenueEmbeddedInTeams(t *testing.T) {
+	app, e := setupTestApp(t)
+	data, teardown := setupTestData(t, app.DB)
+	defer teardown()
+
+	rec := get(e, "/v1/team/details/"+strconv.Itoa(data.homeID))
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var home teamModels.Team
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &home))
+	assert.Equal(t, data.venue.ID, home.VenueID)
+	if assert.NotNil(t, home.Venue) {
+		assert.Equal(t, data.venue, *home.Venue)
+	}
+
+	// Teams without a venue leave it out
+	rec = get(e, "/v1/team/details/"+strconv.Itoa(data.awayID))
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var response map[string]any
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.NotContains(t, response, "venue")
+	assert.NotContains(t, response, "venue_id")
+}
+
+func Test_VenueEmbeddedInFixtures(t *testing.T) {
+	app, e := setupTestApp(t)
+	data, teardown := setupTestData(t, app.DB)
+	defer teardown()
+
+	rec := get(e, "/v1/fixtures?sport_id="+strconv.Itoa(data.sportID))
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var response fixtures.FixtureSearchResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	if assert.Len(t, response.Data, 1) {
+		fixture := response.Data[0]
+		assert.Equal(t, data.fixtureID, fixture.ID)
+		assert.Equal(t, data.venue.ID, fixture.VenueID)
+		if assert.NotNil(t, fixture.Venue) {
+			assert.Equal(t, data.venue, *fixture.Venue)
+		}
+	}
+}
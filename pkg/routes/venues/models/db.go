@@ -0,0 +1,83 @@
+// Venues where teams play their fixtures
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/uptrace/bun"
+)
+
+type Venue struct {
+	bun.BaseModel `bun:"venues"`
+	ID            int    `bun:"id,pk,autoincrement" json:"id"`
+	Name          string `bun:"name" json:"name"`
+	Address       string `bun:"address,nullzero" json:"address,omitempty"`
+	City          string `bun:"city,nullzero" json:"city,omitempty"`
+	Capacity      int    `bun:"capacity,nullzero" json:"capacity,omitempty"`
+	Surface       string `bun:"surface,nullzero" json:"surface,omitempty"`
+	ImageURL      string `bun:"image_url,nullzero" json:"image_url,omitempty"`
+	// Provider and ExternalID identify venues imported from a data provider
+	Provider   string `bun:"provider,nullzero" json:"-"`
+	ExternalID string `bun:"external_id,nullzero" json:"-"`
+}
+
+func GetVenueDetails(db *bun.DB, venueID int) (Venue, error) {
+	var venue Venue
+	err := db.NewSelect().Model(&venue).Where("id = ?", venueID).Scan(context.Background())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Venue{}, errors.New("venue not found")
+		}
+		return Venue{}, err
+	}
+	return venue, nil
+}
+
+// VenueKey identifies a provider venue
+type VenueKey struct {
+	Provider   string
+	ExternalID string
+}
+
+// UpsertVenues saves provider venues keyed by (provider, external_id) and returns
+// their ids. Fields a provider leaves empty don't overwrite what's stored, since
+// some responses, like fixtures, only carry a venue's name and city.
+func UpsertVenues(ctx context.Context, db bun.IDB, venues []Venue) (map[VenueKey]int, error) {
+	ids := make(map[VenueKey]int, len(venues))
+	unique := make([]Venue, 0, len(venues))
+	seen := make(map[VenueKey]bool, len(venues))
+	for _, venue := range venues {
+		key := VenueKey{venue.Provider, venue.ExternalID}
+		if venue.Provider == "" || venue.ExternalID == "" || venue.Name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, venue)
+	}
+	if len(unique) == 0 {
+		return ids, nil
+	}
+
+	var saved []Venue
+	err := db.NewInsert().
+		Model(&unique).
+		On("CONFLICT (provider, external_id) DO UPDATE").
+		Set("name = EXCLUDED.name").
+		Set("address = COALESCE(EXCLUDED.address, venue.address)").
+		Set("city = COALESCE(EXCLUDED.city, venue.city)").
+		Set("capacity = COALESCE(EXCLUDED.capacity, venue.capacity)").
+		Set("surface = COALESCE(EXCLUDED.surface, venue.surface)").
+		Set("image_url = COALESCE(EXCLUDED.image_url, venue.image_url)").
+		Set("updated_at = current_timestamp").
+		Returning("id, provider, external_id").
+		Scan(ctx, &saved)
+	if err != nil {
+		return nil, err
+	}
+	for _, venue := range saved {
+		ids[VenueKey{venue.Provider, venue.ExternalID}] = venue.ID
+	}
+	return ids, nil
+}
@@ -0,0 +1,12 @@
+package venues
+
+import (
+	"mike/pkg/application"
+	"mike/pkg/auth"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterRoutes(e *echo.Echo, app *application.App) {
+	e.GET("/v1/venue/:id", GetVenueDetails, auth.RequireScope(auth.ScopeReadVenues))
+}
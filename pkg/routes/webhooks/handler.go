@@ -0,0 +1,222 @@
+package webhooks
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"mike/pkg/application"
+	"mike/pkg/auth"
+	"mike/pkg/pagination"
+	sportModels "mike/pkg/routes/sports/models"
+	teamModels "mike/pkg/routes/teams/models"
+	webhookModels "mike/pkg/webhooks"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+var deliveryStatuses = map[string]bool{
+	webhookModels.DeliveryPending:   true,
+	webhookModels.DeliveryDelivered: true,
+	webhookModels.DeliveryDead:      true,
+}
+
+// SubscriptionRequest is the body of CreateSubscription
+type SubscriptionRequest struct {
+	URL string `json:"url"`
+	// Secret signs deliveries; one is generated when it's left out
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types"`
+	SportID    int      `json:"sport_id"`
+	TeamID     int      `json:"team_id"`
+}
+
+// CreatedSubscription is a new subscription along with its secret, which isn't shown again
+type CreatedSubscription struct {
+	webhookModels.Subscription
+	Secret string `json:"secret"`
+}
+
+// validateFilters checks that a subscription's sport and team, when given,
+// exist and go together, returning the error message for a bad request
+func validateFilters(app *application.App, sportID int, teamID int) (string, error) {
+	if sportID != 0 {
+		if _, err := sportModels.GetSportDetails(app.DB, sportID); err != nil {
+			if err.Error() == "sport not found" {
+				return "Invalid sport_id", nil
+			}
+			return "", err
+		}
+	}
+	if teamID != 0 {
+		team, err := teamModels.GetTeamDetails(app.DB, teamID)
+		if err != nil {
+			if err.Error() == "team not found" {
+				return "Invalid team_id", nil
+			}
+			return "", err
+		}
+		if sportID != 0 && team.SportId != sportID {
+			return "The team must belong to sport_id", nil
+		}
+	}
+	return "", nil
+}
+
+func CreateSubscription(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	var req SubscriptionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	target, err := webhookModels.CheckURL(c.Request().Context(), req.URL)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if len(req.EventTypes) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "event_types is required"})
+	}
+	for _, eventType := range req.EventTypes {
+		if !slices.Contains(webhookModels.EventTypes, eventType) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Unknown event type " + eventType})
+		}
+	}
+	if req.SportID < 0 || req.TeamID < 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid sport_id or team_id"})
+	}
+	message, err := validateFilters(app, req.SportID, req.TeamID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to validate subscription"})
+	}
+	if message != "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": message})
+	}
+	if req.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to generate secret"})
+		}
+		req.Secret = hex.EncodeToString(secret)
+	}
+
+	apiKey, _ := auth.Key(c)
+	subscription := webhookModels.Subscription{
+		APIKeyID:   apiKey.ID,
+		URL:        target.String(),
+		Secret:     req.Secret,
+		EventTypes: slices.Compact(slices.Sorted(slices.Values(req.EventTypes))),
+		SportID:    req.SportID,
+		TeamID:     req.TeamID,
+		IsActive:   true,
+	}
+	if err := webhookModels.CreateSubscription(app.DB, &subscription); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create subscription"})
+	}
+	return c.JSON(http.StatusCreated, CreatedSubscription{Subscription: subscription, Secret: subscription.Secret})
+}
+
+func GetSubscriptions(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	page, err := pagination.ParseParams(c.QueryParam("limit"), c.QueryParam("cursor"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": pagination.ErrorMessage(err)})
+	}
+	apiKey, _ := auth.Key(c)
+	subscriptions, err := webhookModels.ListSubscriptions(app.DB, apiKey.ID, page)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get subscriptions"})
+	}
+	return c.JSON(http.StatusOK, subscriptions)
+}
+
+func GetSubscription(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid subscription ID"})
+	}
+	apiKey, _ := auth.Key(c)
+	subscription, err := webhookModels.GetSubscription(app.DB, apiKey.ID, id)
+	if err != nil {
+		if err.Error() == "subscription not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Subscription not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get subscription"})
+	}
+	return c.JSON(http.StatusOK, subscription)
+}
+
+func DeleteSubscription(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid subscription ID"})
+	}
+	apiKey, _ := auth.Key(c)
+	if err := webhookModels.DeleteSubscription(app.DB, apiKey.ID, id); err != nil {
+		if err.Error() == "subscription not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Subscription not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete subscription"})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetDeliveries lists a subscription's deliveries, newest first; status=dead
+// gives the ones that ran out of attempts
+func GetDeliveries(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid subscription ID"})
+	}
+	status := c.QueryParam("status")
+	if status != "" && !deliveryStatuses[status] {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid status, must be pending, delivered or dead"})
+	}
+	page, err := pagination.ParseParams(c.QueryParam("limit"), c.QueryParam("cursor"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": pagination.ErrorMessage(err)})
+	}
+	apiKey, _ := auth.Key(c)
+	if _, err := webhookModels.GetSubscription(app.DB, apiKey.ID, id); err != nil {
+		if err.Error() == "subscription not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Subscription not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get subscription"})
+	}
+	deliveries, err := webhookModels.ListDeliveries(app.DB, id, status, page)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get deliveries"})
+	}
+	return c.JSON(http.StatusOK, deliveries)
+}
+
+// RetryDelivery queues a dead delivery to be sent again
+func RetryDelivery(c echo.Context) error {
+	app := c.Get("app").(*application.App)
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid subscription ID"})
+	}
+	deliveryID, err := strconv.ParseInt(c.Param("delivery_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid delivery ID"})
+	}
+	apiKey, _ := auth.Key(c)
+	if _, err := webhookModels.GetSubscription(app.DB, apiKey.ID, id); err != nil {
+		if err.Error() == "subscription not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Subscription not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get subscription"})
+	}
+	delivery, err := webhookModels.RetryDelivery(app.DB, id, deliveryID)
+	if err != nil {
+		if err.Error() == "delivery not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Dead delivery not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retry delivery"})
+	}
+	return c.JSON(http.StatusOK, delivery)
+}
@@ -0,0 +1,197 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mike/config"
+	"mike/pkg/application"
+	"mike/pkg/auth"
+	"mike/pkg/pagination"
+	webhookModels "mike/pkg/webhooks"
+	"mike/utils"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+// publicURL is a public address, so registering it needs no DNS lookup
+const publicURL = "https://93.184.215.14/hooks"
+
+func setupTestApp(t *testing.T) (*application.App, *echo.Echo) {
+	cfg := config.GetConfig()
+	app, err := application.New(cfg)
+	assert.NoError(t, err, "Failed to create application")
+	app.DB = utils.GetDatabase()
+
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("app", app)
+			return next(c)
+		}
+	})
+	e.Use(auth.Authenticate(app.DB))
+	RegisterRoutes(e, app)
+	return app, e
+}
+
+// testData is what setupTestData creates: two sports, each with a team, and
+// two API keys that can manage webhooks
+type testData struct {
+	sportIDs [2]int
+	teamIDs  [2]int
+	keys     [2]string
+}
+
+// setupTestData creates the test data and returns it with a cleanup function
+func setupTestData(t *testing.T, db *bun.DB) (testData, func()) {
+	truncate := func() {
+		for _, table := range []string{"webhook_subscriptions", "fixture_events", "api_keys", "teams", "sports"} {
+			_, _ = db.NewRaw("TRUNCATE TABLE " + table + " CASCADE").Exec(context.Background())
+		}
+	}
+	truncate()
+
+	var data testData
+	for i, owner := range []string{"Partner", "Other partner"} {
+		apiKey := auth.APIKey{Owner: owner, Scopes: []string{auth.ScopeWebhooks}}
+		key, err := auth.CreateKey(db, &apiKey)
+		assert.NoError(t, err, "Error creating API key")
+		data.keys[i] = key
+	}
+	for i, name := range []string{"Test Sport Webhooks", "Other Sport Webhooks"} {
+		sport := struct {
+			bun.BaseModel `bun:"sports"`
+			ID            int    `bun:"id,pk,autoincrement"`
+			Name          string `bun:"name"`
+			IsActive      bool   `bun:"is_active"`
+		}{Name: name, IsActive: true}
+		_, err := db.NewInsert().Model(&sport).Returning("id").Exec(context.Background())
+		assert.NoError(t, err, "Error inserting sport")
+		data.sportIDs[i] = sport.ID
+
+		team := struct {
+			bun.BaseModel `bun:"teams"`
+			ID            int    `bun:"id,pk,autoincrement"`
+			Name          string `bun:"name"`
+			SportId       int    `bun:"sport_id"`
+			IsActive      bool   `bun:"is_active"`
+		}{Name: "Team " + name, SportId: sport.ID, IsActive: true}
+		_, err = db.NewInsert().Model(&team).Returning("id").Exec(context.Background())
+		assert.NoError(t, err, "Error inserting team")
+		data.teamIDs[i] = team.ID
+	}
+	return data, truncate
+}
+
+// request sends a request authenticated with key with a JSON body, if body
+// isn't empty, and returns the response
+func request(e *echo.Echo, key, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+key)
+	if body != "" {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func Test_CreateSubscription(t *testing.T) {
+	app, e := setupTestApp(t)
+	data, teardown := setupTestData(t, app.DB)
+	defer teardown()
+	sportIDs, teamIDs := data.sportIDs, data.teamIDs
+
+	subscription := func(filters string) string {
+		return fmt.Sprintf(`{"url": %q, "event_types": ["fixture.created"]%s}`, publicURL, filters)
+	}
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{name: "every fixture", body: subscription(""), expectedStatus: http.StatusCreated},
+		{name: "a sport's team", body: subscription(fmt.Sprintf(`, "sport_id": %d, "team_id": %d`, sportIDs[0], teamIDs[0])), expectedStatus: http.StatusCreated},
+		{name: "invalid body", body: `{"url": `, expectedStatus: http.StatusBadRequest, expectedError: "Invalid request body"},
+		{name: "private url", body: `{"url": "http://127.0.0.1/hooks", "event_types": ["fixture.created"]}`, expectedStatus: http.StatusBadRequest},
+		{name: "unknown event type", body: fmt.Sprintf(`{"url": %q, "event_types": ["fixture.deleted"]}`, publicURL), expectedStatus: http.StatusBadRequest, expectedError: "Unknown event type fixture.deleted"},
+		{name: "unknown sport", body: subscription(`, "sport_id": 999999`), expectedStatus: http.StatusBadRequest, expectedError: "Invalid sport_id"},
+		{name: "unknown team", body: subscription(`, "team_id": 999999`), expectedStatus: http.StatusBadRequest, expectedError: "Invalid team_id"},
+		{name: "team of another sport", body: subscription(fmt.Sprintf(`, "sport_id": %d, "team_id": %d`, sportIDs[0], teamIDs[1])), expectedStatus: http.StatusBadRequest, expectedError: "The team must belong to sport_id"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := request(e, data.keys[0], http.MethodPost, "/v1/webhooks", test.body)
+			assert.Equal(t, test.expectedStatus, rec.Code, rec.Body.String())
+			if test.expectedError != "" {
+				var response map[string]string
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, test.expectedError, response["error"])
+			}
+		})
+	}
+}
+
+func Test_WebhooksNeedWebhooksScope(t *testing.T) {
+	app, e := setupTestApp(t)
+	_, teardown := setupTestData(t, app.DB)
+	defer teardown()
+
+	readKey := auth.APIKey{Owner: "Reader", Scopes: []string{auth.ScopeReadFixtures}}
+	key, err := auth.CreateKey(app.DB, &readKey)
+	assert.NoError(t, err)
+	rec := request(e, key, http.MethodGet, "/v1/webhooks", "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = request(e, key, http.MethodPost, "/v1/webhooks", fmt.Sprintf(`{"url": %q, "event_types": ["fixture.created"]}`, publicURL))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func Test_SubscriptionsBelongToTheirKey(t *testing.T) {
+	app, e := setupTestApp(t)
+	data, teardown := setupTestData(t, app.DB)
+	defer teardown()
+	owner, other := data.keys[0], data.keys[1]
+
+	body := fmt.Sprintf(`{"url": %q, "event_types": ["fixture.created"]}`, publicURL)
+	rec := request(e, owner, http.MethodPost, "/v1/webhooks", body)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created webhookModels.Subscription
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	rec = request(e, other, http.MethodPost, "/v1/webhooks", body)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	// Each key only lists its own
+	for _, key := range []string{owner, other} {
+		rec = request(e, key, http.MethodGet, "/v1/webhooks", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var page pagination.Page[webhookModels.Subscription]
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		assert.Len(t, page.Data, 1)
+	}
+
+	// Another key can't see or touch the subscription
+	path := "/v1/webhooks/" + strconv.Itoa(created.ID)
+	for _, route := range []struct{ method, path string }{
+		{http.MethodGet, path},
+		{http.MethodGet, path + "/deliveries"},
+		{http.MethodPost, path + "/deliveries/1/retry"},
+		{http.MethodDelete, path},
+	} {
+		rec = request(e, other, route.method, route.path, "")
+		assert.Equal(t, http.StatusNotFound, rec.Code, route.method+" "+route.path)
+	}
+
+	rec = request(e, owner, http.MethodGet, path, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = request(e, owner, http.MethodDelete, path, "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
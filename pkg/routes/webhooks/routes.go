@@ -0,0 +1,17 @@
+package webhooks
+
+import (
+	"mike/pkg/application"
+	"mike/pkg/auth"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterRoutes(e *echo.Echo, app *application.App) {
+	e.POST("/v1/webhooks", CreateSubscription, auth.RequireScope(auth.ScopeWebhooks))
+	e.GET("/v1/webhooks", GetSubscriptions, auth.RequireScope(auth.ScopeWebhooks))
+	e.GET("/v1/webhooks/:id", GetSubscription, auth.RequireScope(auth.ScopeWebhooks))
+	e.DELETE("/v1/webhooks/:id", DeleteSubscription, auth.RequireScope(auth.ScopeWebhooks))
+	e.GET("/v1/webhooks/:id/deliveries", GetDeliveries, auth.RequireScope(auth.ScopeWebhooks))
+	e.POST("/v1/webhooks/:id/deliveries/:delivery_id/retry", RetryDelivery, auth.RequireScope(auth.ScopeWebhooks))
+}
@@ -0,0 +1,178 @@
+// Package scheduler keeps fixtures up to date from inside the server process
+package scheduler
+
+import (
+	"context"
+	"log"
+	"mike/config"
+	"mike/pkg/ingest"
+	"mike/pkg/providers"
+	competitionModels "mike/pkg/routes/competitions/models"
+	fixtureModels "mike/pkg/routes/fixtures/models"
+	"strconv"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// syncLockKey is the advisory lock every sync job takes. The jobs share it
+// because concurrent syncs upserting overlapping fixtures can deadlock on their
+// row locks; a live update due during an upcoming refresh is skipped instead.
+const syncLockKey int64 = 0x6d696b6501
+
+// inPlayWindow is how long after kickoff a fixture that hasn't finished is
+// considered in play
+const inPlayWindow = 3 * time.Hour
+
+// Scheduler periodically refreshes upcoming fixtures and, more often, fixtures
+// in play. Every job run takes a Postgres advisory lock first, so only one sync
+// runs at a time, even with several replicas.
+type Scheduler struct {
+	db               *bun.DB
+	registry         map[string]providers.Provider
+	competitions     []ingest.CompetitionConfig
+	upcomingInterval time.Duration
+	upcomingWindow   time.Duration
+	liveInterval     time.Duration
+	now              func() time.Time
+}
+
+func New(cfg *config.SyncConfig, db *bun.DB, registry map[string]providers.Provider, competitions []ingest.CompetitionConfig) *Scheduler {
+	return &Scheduler{
+		db:               db,
+		registry:         registry,
+		competitions:     competitions,
+		upcomingInterval: time.Duration(cfg.UpcomingIntervalMinutes) * time.Minute,
+		upcomingWindow:   time.Duration(cfg.UpcomingDays) * 24 * time.Hour,
+		liveInterval:     time.Duration(cfg.LiveIntervalSeconds) * time.Second,
+		now:              time.Now,
+	}
+}
+
+// Run syncs on both cadences until ctx is cancelled. Upcoming fixtures are
+// refreshed once straight away.
+func (s *Scheduler) Run(ctx context.Context) {
+	log.Printf("Fixture sync scheduler started: upcoming every %s, live every %s", s.upcomingInterval, s.liveInterval)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.every(ctx, s.liveInterval, false, func() { s.runLocked(ctx, "live", s.syncLive) })
+	}()
+	s.every(ctx, s.upcomingInterval, true, func() { s.runLocked(ctx, "upcoming", s.syncUpcoming) })
+	<-done
+	log.Println("Fixture sync scheduler stopped")
+}
+
+// every calls job on each tick of interval until ctx is cancelled
+func (s *Scheduler) every(ctx context.Context, interval time.Duration, immediately bool, job func()) {
+	if immediately {
+		job()
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			job()
+		}
+	}
+}
+
+// runLocked runs job if it can take the sync advisory lock, and skips it otherwise
+func (s *Scheduler) runLocked(ctx context.Context, name string, job func(ctx context.Context)) {
+	ran, err := WithAdvisoryLock(ctx, s.db, syncLockKey, job)
+	if err != nil {
+		log.Printf("Fixture sync %s: %v", name, err)
+		return
+	}
+	if !ran {
+		log.Printf("Fixture sync %s: skipped, another sync holds the lock", name)
+	}
+}
+
+func (s *Scheduler) syncUpcoming(ctx context.Context) {
+	// Start back at the in-play window, so fixtures the live sync missed finishing are caught
+	now := s.now()
+	from := now.Add(-inPlayWindow)
+	to := now.Add(s.upcomingWindow)
+	for _, c := range s.competitions {
+		p, ok := s.registry[c.Provider]
+		if !ok {
+			log.Printf("Fixture sync upcoming %s: unknown provider %q", c.Name, c.Provider)
+			continue
+		}
+		summary, err := ingest.SyncFixturesBetween(ctx, s.db, p, c.Competition(), from, to)
+		if err != nil {
+			log.Printf("Fixture sync upcoming %s: %v", c.Name, err)
+			continue
+		}
+		log.Printf("Fixture sync upcoming %s", summary)
+	}
+}
+
+// syncLive refreshes every fixture that kicked off within the in-play window,
+// not only those the provider still lists as live, so fixtures that have just
+// finished get their final status and score
+func (s *Scheduler) syncLive(ctx context.Context) {
+	now := s.now()
+	for _, c := range s.competitions {
+		p, ok := s.registry[c.Provider]
+		if !ok {
+			continue
+		}
+		// Skip the provider call, and the request quota it uses, when nothing in
+		// this competition is being played
+		season, err := competitionModels.GetProviderSeason(s.db, p.Name(), strconv.Itoa(c.LeagueID), c.Season)
+		if err != nil {
+			// Not synced yet, so nothing of it can be in play
+			if err.Error() != "season not found" {
+				log.Printf("Fixture sync live %s: %v", c.Name, err)
+			}
+			continue
+		}
+		inPlay, err := fixtureModels.CountFixturesInPlay(s.db, season.ID, now, inPlayWindow)
+		if err != nil {
+			log.Printf("Fixture sync live %s: %v", c.Name, err)
+			continue
+		}
+		if inPlay == 0 {
+			continue
+		}
+		summary, err := ingest.SyncFixturesBetween(ctx, s.db, p, c.Competition(), now.Add(-inPlayWindow), now)
+		if err != nil {
+			log.Printf("Fixture sync live %s: %v", c.Name, err)
+			continue
+		}
+		log.Printf("Fixture sync live %s", summary)
+	}
+}
+
+// WithAdvisoryLock runs fn while holding the session-level advisory lock key,
+// returning false without running it when another session holds the lock.
+func WithAdvisoryLock(ctx context.Context, db *bun.DB, key int64, fn func(ctx context.Context)) (bool, error) {
+	// Session locks belong to a connection, so take and release it on the same one
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.NewRaw("SELECT pg_try_advisory_lock(?)", key).Scan(ctx, &locked); err != nil {
+		return false, err
+	}
+	if !locked {
+		return false, nil
+	}
+	defer func() {
+		// Unlock even if ctx was cancelled while fn ran
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key); err != nil {
+			log.Printf("Error releasing advisory lock %d: %v", key, err)
+		}
+	}()
+
+	fn(ctx)
+	return true, nil
+}
@@ -0,0 +1,171 @@
+package scheduler
+
+import (
+	"context"
+	"mike/pkg/ingest"
+	"mike/pkg/providers"
+	"mike/pkg/providers/fake"
+	competitionModels "mike/pkg/routes/competitions/models"
+	fixtureModels "mike/pkg/routes/fixtures/models"
+	"mike/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+// windowProvider serves its fixtures for any window and records the windows asked for
+type windowProvider struct {
+	fake.Provider
+	windows [][2]time.Time
+}
+
+func (p *windowProvider) ListFixturesBetween(ctx context.Context, competition providers.Competition, from, to time.Time) ([]fixtureModels.Fixture, []providers.Skip, error) {
+	p.windows = append(p.windows, [2]time.Time{from, to})
+	return p.ListFixtures(ctx, competition)
+}
+
+// setupSchedulerTestData creates a sport and two teams and returns their ids and a cleanup function
+func setupSchedulerTestData(t *testing.T, db *bun.DB) (int, [2]int, func()) {
+	truncate := func() {
+		_, _ = db.NewRaw("TRUNCATE TABLE fixtures CASCADE").Exec(context.Background())
+		_, _ = db.NewRaw("TRUNCATE TABLE teams CASCADE").Exec(context.Background())
+		_, _ = db.NewRaw("TRUNCATE TABLE sports CASCADE").Exec(context.Background())
+		_, _ = db.NewRaw("TRUNCATE TABLE sync_runs CASCADE").Exec(context.Background())
+	}
+	truncate()
+
+	sport := struct {
+		bun.BaseModel `bun:"sports"`
+		ID            int    `bun:"id,pk,autoincrement"`
+		Name          string `bun:"name"`
+		IsActive      bool   `bun:"is_active"`
+	}{Name: "Test Sport Scheduler", IsActive: true}
+	_, err := db.NewInsert().Model(&sport).Returning("id").Exec(context.Background())
+	assert.NoError(t, err)
+
+	var teamIDs [2]int
+	for i, name := range []string{"Home Scheduler", "Away Scheduler"} {
+		team := struct {
+			bun.BaseModel `bun:"teams"`
+			ID            int    `bun:"id,pk,autoincrement"`
+			Name          string `bun:"name"`
+			SportId       int    `bun:"sport_id"`
+			IsActive      bool   `bun:"is_active"`
+		}{Name: name, SportId: sport.ID, IsActive: true}
+		_, err := db.NewInsert().Model(&team).Returning("id").Exec(context.Background())
+		assert.NoError(t, err)
+		teamIDs[i] = team.ID
+	}
+	return sport.ID, teamIDs, truncate
+}
+
+func Test_SyncLive(t *testing.T) {
+	db := utils.GetDatabase()
+	sportID, teamIDs, teardown := setupSchedulerTestData(t, db)
+	defer teardown()
+
+	ctx := context.Background()
+	now := time.Date(2025, 8, 17, 0, 30, 0, 0, time.UTC)
+	config := ingest.CompetitionConfig{Name: "Test League", Provider: "fake", LeagueID: 39, Season: "2025", SportID: sportID}
+	provider := &windowProvider{}
+	s := &Scheduler{
+		db:           db,
+		registry:     map[string]providers.Provider{"fake": provider},
+		competitions: []ingest.CompetitionConfig{config},
+		now:          func() time.Time { return now },
+	}
+
+	season, err := competitionModels.EnsureSeason(ctx, db, competitionModels.Competition{
+		SportID: sportID, Name: config.Name, Provider: provider.Name(), ExternalID: "39",
+	}, config.Season)
+	assert.NoError(t, err)
+	otherSeason, err := competitionModels.EnsureSeason(ctx, db, competitionModels.Competition{
+		SportID: sportID, Name: "Other League", Provider: provider.Name(), ExternalID: "40",
+	}, config.Season)
+	assert.NoError(t, err)
+
+	// A match being played in another competition of the sport doesn't need this one fetched
+	other := fixtureModels.Fixture{
+		SportID: sportID, SeasonID: otherSeason.ID, TeamID1: teamIDs[1], TeamID2: teamIDs[0],
+		DateTime: now.Add(-time.Hour), Status: fixtureModels.StatusLive,
+		Provider: provider.Name(), ExternalID: "2",
+	}
+	_, err = db.NewInsert().Model(&other).Returning("id").Exec(ctx)
+	assert.NoError(t, err)
+	s.syncLive(ctx)
+	assert.Empty(t, provider.windows)
+
+	// Kicked off before midnight and still live when last synced
+	fixture := fixtureModels.Fixture{
+		SportID: sportID, SeasonID: season.ID, TeamID1: teamIDs[0], TeamID2: teamIDs[1],
+		DateTime: now.Add(-2 * time.Hour), Status: fixtureModels.StatusLive,
+		Provider: provider.Name(), ExternalID: "1",
+	}
+	_, err = db.NewInsert().Model(&fixture).Returning("id").Exec(ctx)
+	assert.NoError(t, err)
+
+	// The provider no longer lists it as live, but has its final score for the window
+	homeScore, awayScore := 2, 1
+	finished := fixture
+	finished.ID = 0
+	finished.Status = fixtureModels.StatusFinished
+	finished.HomeScore = &homeScore
+	finished.AwayScore = &awayScore
+	provider.Fixtures = map[providers.Competition][]fixtureModels.Fixture{config.Competition(): {finished}}
+
+	s.syncLive(ctx)
+	if assert.Len(t, provider.windows, 1) {
+		assert.Equal(t, [2]time.Time{now.Add(-inPlayWindow), now}, provider.windows[0])
+	}
+	var stored fixtureModels.Fixture
+	assert.NoError(t, db.NewSelect().Model(&stored).Where("id = ?", fixture.ID).Scan(ctx))
+	assert.Equal(t, fixtureModels.StatusFinished, stored.Status)
+	if assert.NotNil(t, stored.HomeScore) && assert.NotNil(t, stored.AwayScore) {
+		assert.Equal(t, 2, *stored.HomeScore)
+		assert.Equal(t, 1, *stored.AwayScore)
+	}
+
+	// With nothing left in play the provider isn't called
+	s.syncLive(ctx)
+	assert.Len(t, provider.windows, 1)
+}
+
+func Test_RunLockedSkipsWhileLockIsHeld(t *testing.T) {
+	db := utils.GetDatabase()
+	ctx := context.Background()
+	s := &Scheduler{db: db, now: time.Now}
+
+	// Another replica, on a connection of its own, holds the lock
+	conn, err := db.Conn(ctx)
+	assert.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", syncLockKey)
+	assert.NoError(t, err)
+
+	// Both jobs share the lock, so neither runs
+	runs := 0
+	job := func(ctx context.Context) { runs++ }
+	s.runLocked(ctx, "live", job)
+	s.runLocked(ctx, "upcoming", job)
+	assert.Equal(t, 0, runs)
+
+	_, err = conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", syncLockKey)
+	assert.NoError(t, err)
+	s.runLocked(ctx, "live", job)
+	assert.Equal(t, 1, runs)
+
+	// A job running holds off the other one
+	s.runLocked(ctx, "upcoming", func(ctx context.Context) {
+		runs++
+		s.runLocked(ctx, "live", job)
+	})
+	assert.Equal(t, 2, runs)
+
+	// The lock is released once the job is done
+	ran, err := WithAdvisoryLock(ctx, db, syncLockKey, job)
+	assert.NoError(t, err)
+	assert.True(t, ran)
+	assert.Equal(t, 3, runs)
+}
@@ -6,18 +6,46 @@ import (
 	"time"
 
 	"mike/pkg/application"
+	"mike/pkg/auth"
+	"mike/pkg/ratelimit"
+	"mike/pkg/routes/admin"
+	"mike/pkg/routes/competitions"
 	"mike/pkg/routes/fixtures"
 	"mike/pkg/routes/health"
 	"mike/pkg/routes/sports"
 	"mike/pkg/routes/teams"
+	"mike/pkg/routes/venues"
+	"mike/pkg/routes/webhooks"
 
 	"github.com/labstack/echo/v4"
 )
 
-func CreateEcho() *echo.Echo {
+func CreateEcho(app *application.App) (*echo.Echo, error) {
 	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+	if app.Config.RateLimit.TrustProxy {
+		e.IPExtractor = echo.ExtractIPFromXFFHeader()
+	}
 
-	return e
+	var limiter *ratelimit.Limiter
+	if app.Config.RateLimit.Enabled {
+		var err error
+		limiter, err = ratelimit.FromConfig(app.Config.RateLimit, app.DB)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up rate limiting: %w", err)
+		}
+		// By IP before authentication, so a client can't dodge the limit by
+		// making up a new key for every request
+		e.Use(limiter.ByIP("/health"))
+	}
+	// Every route needs an API key but the health check; routes add the scope
+	// they need. Feeds also take a feed token in the query string.
+	e.Use(auth.AuthenticateFeeds(app.DB, fixtures.FeedPaths...))
+	e.Use(auth.Authenticate(app.DB, "/health"))
+	if limiter != nil {
+		e.Use(limiter.ByKey())
+	}
+	return e, nil
 }
 
 func RegisterRoutes(app *application.App, e *echo.Echo) error {
@@ -33,12 +61,19 @@ func RegisterRoutes(app *application.App, e *echo.Echo) error {
 	sports.RegisterRoutes(e, app)
 	teams.RegisterRoutes(e, app)
 	fixtures.RegisterRoutes(e, app)
+	competitions.RegisterRoutes(e, app)
+	venues.RegisterRoutes(e, app)
+	admin.RegisterRoutes(e, app)
+	webhooks.RegisterRoutes(e, app)
 	return nil
 }
 
 // New creates an instance of the HTTP server for an Application
 func New(app *application.App) (*http.Server, error) {
-	e := CreateEcho()
+	e, err := CreateEcho(app)
+	if err != nil {
+		return nil, err
+	}
 	if err := RegisterRoutes(app, e); err != nil {
 		return nil, fmt.Errorf("failed to register routes: %w", err)
 	}
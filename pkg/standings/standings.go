@@ -0,0 +1,264 @@
+// Package standings computes league tables from finished fixtures
+package standings
+
+import (
+	"fmt"
+	"mike/pkg/routes/fixtures/models"
+	"sort"
+	"strings"
+)
+
+// Tiebreakers order teams level on everything before them
+const (
+	Points         = "points"
+	GoalDifference = "goal_difference"
+	GoalsFor       = "goals_for"
+	Wins           = "wins"
+	WinPercentage  = "win_percentage"
+	// HeadToHead ranks tied teams by the points they took off each other
+	HeadToHead = "head_to_head"
+)
+
+var tiebreakers = map[string]bool{
+	Points: true, GoalDifference: true, GoalsFor: true, Wins: true, WinPercentage: true, HeadToHead: true,
+}
+
+// formLength is how many recent results Row.Form shows
+const formLength = 5
+
+// Rules are how a competition awards points and breaks ties
+type Rules struct {
+	Win  int `json:"win"`
+	Draw int `json:"draw"`
+	Loss int `json:"loss"`
+	// OvertimeLoss replaces Loss for a defeat decided in one of OvertimePeriods
+	OvertimeLoss    int      `json:"overtime_loss,omitempty"`
+	OvertimePeriods []string `json:"overtime_periods,omitempty"`
+	// Tiebreakers are applied in order; teams still level are ordered by name
+	Tiebreakers []string `json:"tiebreakers"`
+}
+
+// Validate checks that every tiebreaker is known
+func (r Rules) Validate() error {
+	for _, tb := range r.Tiebreakers {
+		if !tiebreakers[tb] {
+			return fmt.Errorf("unknown tiebreaker %q", tb)
+		}
+	}
+	return nil
+}
+
+// Presets are the default rules for each sport, keyed by lower-case sport name
+var Presets = map[string]Rules{
+	"soccer": {
+		Win: 3, Draw: 1, Loss: 0,
+		Tiebreakers: []string{Points, GoalDifference, GoalsFor, HeadToHead, Wins},
+	},
+	"hockey": {
+		Win: 2, Draw: 1, Loss: 0, OvertimeLoss: 1,
+		OvertimePeriods: []string{"overtime", "shootout"},
+		Tiebreakers:     []string{Points, Wins, HeadToHead, GoalDifference, GoalsFor},
+	},
+	"basketball": {
+		Win: 1, Loss: 0,
+		Tiebreakers: []string{WinPercentage, HeadToHead, GoalDifference},
+	},
+	"baseball": {
+		Win: 1, Loss: 0,
+		Tiebreakers: []string{WinPercentage, HeadToHead, GoalDifference},
+	},
+	"football": {
+		Win: 1, Loss: 0,
+		Tiebreakers: []string{WinPercentage, HeadToHead, GoalDifference, GoalsFor},
+	},
+}
+
+// PresetFor returns the rules for a sport, falling back to soccer's
+func PresetFor(sport string) Rules {
+	if rules, ok := Presets[strings.ToLower(sport)]; ok {
+		return rules
+	}
+	return Presets["soccer"]
+}
+
+// Row is one team's line in the table. Goals are points or runs in sports
+// that don't score goals.
+type Row struct {
+	Position       int    `json:"position"`
+	TeamID         int    `json:"team_id"`
+	TeamName       string `json:"team_name"`
+	Played         int    `json:"played"`
+	Won            int    `json:"won"`
+	Drawn          int    `json:"drawn"`
+	Lost           int    `json:"lost"`
+	OvertimeLost   int    `json:"overtime_lost,omitempty"`
+	GoalsFor       int    `json:"goals_for"`
+	GoalsAgainst   int    `json:"goals_against"`
+	GoalDifference int    `json:"goal_difference"`
+	Points         int    `json:"points"`
+	// Form is the last few results, oldest first: W, D or L
+	Form string `json:"form"`
+}
+
+func (r *Row) winPercentage() float64 {
+	if r.Played == 0 {
+		return 0
+	}
+	// Draws count as half a win, as in the NFL
+	return (float64(r.Won) + float64(r.Drawn)/2) / float64(r.Played)
+}
+
+// result is one team's outcome in a fixture
+type result struct {
+	scored, conceded int
+	overtime         bool
+}
+
+// Compute builds the table for teams from fixtures, which must be ordered by
+// kickoff. Fixtures that aren't finished, have no score or involve a team not
+// in teams are ignored. teams maps team ids to names; every team gets a row
+// even without a finished fixture.
+func Compute(fixtures []models.Fixture, teams map[int]string, rules Rules) []Row {
+	rows := make(map[int]*Row, len(teams))
+	for id, name := range teams {
+		rows[id] = &Row{TeamID: id, TeamName: name}
+	}
+
+	var played []models.Fixture
+	for _, fixture := range fixtures {
+		if fixture.Status != models.StatusFinished || fixture.HomeScore == nil || fixture.AwayScore == nil {
+			continue
+		}
+		home, away := rows[fixture.TeamID1], rows[fixture.TeamID2]
+		if home == nil || away == nil {
+			continue
+		}
+		played = append(played, fixture)
+		overtime := decidedInOvertime(fixture, rules.OvertimePeriods)
+		home.record(result{*fixture.HomeScore, *fixture.AwayScore, overtime}, rules)
+		away.record(result{*fixture.AwayScore, *fixture.HomeScore, overtime}, rules)
+	}
+
+	table := make([]*Row, 0, len(rows))
+	for _, row := range rows {
+		if len(row.Form) > formLength {
+			row.Form = row.Form[len(row.Form)-formLength:]
+		}
+		table = append(table, row)
+	}
+	rank(table, rules.Tiebreakers, played, rules)
+
+	out := make([]Row, len(table))
+	for i, row := range table {
+		row.Position = i + 1
+		out[i] = *row
+	}
+	return out
+}
+
+func (r *Row) record(res result, rules Rules) {
+	r.Played++
+	r.GoalsFor += res.scored
+	r.GoalsAgainst += res.conceded
+	r.GoalDifference = r.GoalsFor - r.GoalsAgainst
+	switch {
+	case res.scored > res.conceded:
+		r.Won++
+		r.Points += rules.Win
+		r.Form += "W"
+	case res.scored == res.conceded:
+		r.Drawn++
+		r.Points += rules.Draw
+		r.Form += "D"
+	case res.overtime:
+		r.OvertimeLost++
+		r.Points += rules.OvertimeLoss
+		r.Form += "L"
+	default:
+		r.Lost++
+		r.Points += rules.Loss
+		r.Form += "L"
+	}
+}
+
+// decidedInOvertime reports whether a fixture's score includes one of the overtime periods
+func decidedInOvertime(fixture models.Fixture, periods []string) bool {
+	for _, score := range fixture.Scores {
+		for _, period := range periods {
+			if score.Period == period {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// rank sorts rows by the first tiebreaker, then ranks each group still level
+// by the remaining ones. Head to head only counts fixtures between the teams
+// in the group being ranked.
+func rank(rows []*Row, tbs []string, fixtures []models.Fixture, rules Rules) {
+	if len(rows) < 2 {
+		return
+	}
+	if len(tbs) == 0 {
+		sort.SliceStable(rows, func(i, j int) bool {
+			if rows[i].TeamName != rows[j].TeamName {
+				return rows[i].TeamName < rows[j].TeamName
+			}
+			return rows[i].TeamID < rows[j].TeamID
+		})
+		return
+	}
+
+	key := tiebreakKey(tbs[0], rows, fixtures, rules)
+	sort.SliceStable(rows, func(i, j int) bool {
+		return key[rows[i].TeamID] > key[rows[j].TeamID]
+	})
+	for start := 0; start < len(rows); {
+		end := start + 1
+		for end < len(rows) && key[rows[end].TeamID] == key[rows[start].TeamID] {
+			end++
+		}
+		rank(rows[start:end], tbs[1:], fixtures, rules)
+		start = end
+	}
+}
+
+// tiebreakKey scores each row for a tiebreaker, higher ranking first
+func tiebreakKey(tb string, rows []*Row, fixtures []models.Fixture, rules Rules) map[int]float64 {
+	key := make(map[int]float64, len(rows))
+	if tb == HeadToHead {
+		group := make(map[int]*Row, len(rows))
+		for _, row := range rows {
+			group[row.TeamID] = &Row{TeamID: row.TeamID}
+		}
+		for _, fixture := range fixtures {
+			home, away := group[fixture.TeamID1], group[fixture.TeamID2]
+			if home == nil || away == nil {
+				continue
+			}
+			overtime := decidedInOvertime(fixture, rules.OvertimePeriods)
+			home.record(result{*fixture.HomeScore, *fixture.AwayScore, overtime}, rules)
+			away.record(result{*fixture.AwayScore, *fixture.HomeScore, overtime}, rules)
+		}
+		for id, row := range group {
+			key[id] = float64(row.Points)
+		}
+		return key
+	}
+	for _, row := range rows {
+		switch tb {
+		case Points:
+			key[row.TeamID] = float64(row.Points)
+		case GoalDifference:
+			key[row.TeamID] = float64(row.GoalDifference)
+		case GoalsFor:
+			key[row.TeamID] = float64(row.GoalsFor)
+		case Wins:
+			key[row.TeamID] = float64(row.Won)
+		case WinPercentage:
+			key[row.TeamID] = row.winPercentage()
+		}
+	}
+	return key
+}
@@ -0,0 +1,138 @@
+package standings
+
+import (
+	"mike/pkg/routes/fixtures/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var kickoff = time.Date(2025, 8, 16, 15, 0, 0, 0, time.UTC)
+
+func finished(round, home, away, homeScore, awayScore int, periods ...string) models.Fixture {
+	fixture := models.Fixture{
+		TeamID1:   home,
+		TeamID2:   away,
+		DateTime:  kickoff.AddDate(0, 0, 7*round),
+		Status:    models.StatusFinished,
+		HomeScore: &homeScore,
+		AwayScore: &awayScore,
+	}
+	for i, period := range periods {
+		fixture.Scores = append(fixture.Scores, models.FixtureScore{Period: period, Sequence: i + 1})
+	}
+	return fixture
+}
+
+func positions(rows []Row) []string {
+	names := make([]string, len(rows))
+	for i, row := range rows {
+		names[i] = row.TeamName
+	}
+	return names
+}
+
+func Test_Compute_CountsResults(t *testing.T) {
+	teams := map[int]string{1: "Arsenal", 2: "Chelsea", 3: "Liverpool", 4: "Fulham"}
+	fixtures := []models.Fixture{
+		finished(0, 1, 2, 2, 0),
+		finished(0, 3, 4, 1, 1),
+		finished(1, 2, 3, 1, 3),
+		finished(1, 4, 1, 0, 0),
+		{TeamID1: 1, TeamID2: 3, DateTime: kickoff.AddDate(0, 0, 14), Status: models.StatusScheduled},
+	}
+
+	rows := Compute(fixtures, teams, Presets["soccer"])
+
+	assert.Equal(t, []string{"Liverpool", "Arsenal", "Fulham", "Chelsea"}, positions(rows))
+	liverpool := rows[0]
+	assert.Equal(t, Row{
+		Position: 1, TeamID: 3, TeamName: "Liverpool",
+		Played: 2, Won: 1, Drawn: 1, GoalsFor: 4, GoalsAgainst: 2, GoalDifference: 2, Points: 4,
+		Form: "DW",
+	}, liverpool)
+	assert.Equal(t, 4, rows[1].Points)
+	assert.Equal(t, 2, rows[1].GoalDifference)
+	assert.Equal(t, 0, rows[3].Points)
+	assert.Equal(t, "LL", rows[3].Form)
+}
+
+func Test_Compute_IncludesTeamsWithoutFixtures(t *testing.T) {
+	rows := Compute(nil, map[int]string{1: "Burnley", 2: "Arsenal"}, Presets["soccer"])
+
+	assert.Equal(t, []string{"Arsenal", "Burnley"}, positions(rows))
+	assert.Equal(t, 0, rows[0].Played)
+	assert.Equal(t, 1, rows[0].Position)
+}
+
+func Test_Compute_BreaksTiesHeadToHead(t *testing.T) {
+	teams := map[int]string{1: "Arsenal", 2: "Chelsea", 3: "Fulham"}
+	rules := Rules{Win: 3, Draw: 1, Tiebreakers: []string{Points, HeadToHead, GoalDifference}}
+	// Everyone wins once and loses once
+	fixtures := []models.Fixture{
+		finished(0, 2, 1, 1, 0),
+		finished(1, 1, 3, 5, 0),
+		finished(2, 3, 2, 1, 0),
+	}
+
+	rows := Compute(fixtures, teams, rules)
+
+	assert.Equal(t, 3, rows[0].Points)
+	assert.Equal(t, 3, rows[1].Points)
+	assert.Equal(t, 3, rows[2].Points)
+	// Head to head between all three covers every fixture, so goal difference decides
+	assert.Equal(t, []string{"Arsenal", "Chelsea", "Fulham"}, positions(rows))
+
+	// Between two teams level on points, the one that won their meeting goes first
+	rows = Compute(fixtures[:1], map[int]string{1: "Arsenal", 2: "Chelsea"}, Rules{Win: 3, Tiebreakers: []string{HeadToHead}})
+	assert.Equal(t, []string{"Chelsea", "Arsenal"}, positions(rows))
+}
+
+func Test_Compute_AwardsOvertimeLosses(t *testing.T) {
+	teams := map[int]string{1: "Boston Bruins", 2: "Montreal Canadiens"}
+	fixtures := []models.Fixture{
+		finished(0, 1, 2, 3, 2, "1", "2", "3", "overtime"),
+		finished(1, 2, 1, 4, 1, "1", "2", "3"),
+	}
+
+	rows := Compute(fixtures, teams, Presets["hockey"])
+
+	assert.Equal(t, []string{"Montreal Canadiens", "Boston Bruins"}, positions(rows))
+	assert.Equal(t, 3, rows[0].Points, "a win and an overtime loss")
+	assert.Equal(t, 1, rows[0].OvertimeLost)
+	assert.Equal(t, 2, rows[1].Points)
+	assert.Equal(t, 1, rows[1].Lost)
+}
+
+func Test_Compute_RanksByWinPercentage(t *testing.T) {
+	teams := map[int]string{1: "Yankees", 2: "Red Sox", 3: "Dodgers"}
+	fixtures := []models.Fixture{
+		finished(0, 1, 2, 5, 3),
+		finished(1, 2, 1, 2, 1),
+		finished(2, 3, 2, 4, 0),
+	}
+
+	rows := Compute(fixtures, teams, Presets["baseball"])
+
+	assert.Equal(t, "Dodgers", rows[0].TeamName)
+	assert.Equal(t, []string{"Dodgers", "Yankees", "Red Sox"}, positions(rows))
+}
+
+func Test_Compute_KeepsLastFiveResultsInForm(t *testing.T) {
+	teams := map[int]string{1: "Arsenal", 2: "Chelsea"}
+	var fixtures []models.Fixture
+	for round, score := range []int{1, 1, 0, 0, 2, 3} {
+		fixtures = append(fixtures, finished(round, 1, 2, score, 1))
+	}
+
+	rows := Compute(fixtures, teams, Presets["soccer"])
+
+	assert.Equal(t, "DLLWW", rows[0].Form)
+	assert.Equal(t, 6, rows[0].Played)
+}
+
+func Test_Rules_Validate(t *testing.T) {
+	assert.NoError(t, Presets["soccer"].Validate())
+	assert.ErrorContains(t, Rules{Tiebreakers: []string{"coin_toss"}}.Validate(), "coin_toss")
+}
@@ -0,0 +1,211 @@
+// Package webhooks delivers fixture events to the endpoints API consumers subscribe
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mike/config"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Headers sent with every delivery
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+const (
+	// claimLease is how long a claimed delivery is left alone by other replicas
+	claimLease = 2 * time.Minute
+	// claimBatch is how many deliveries are sent per poll
+	claimBatch = 50
+	// maxBackoff caps the wait between attempts
+	maxBackoff = 6 * time.Hour
+	// maxErrorBody is how much of a failed response is kept in last_error
+	maxErrorBody = 512
+	// pruneInterval is how often events older than the retention are deleted
+	pruneInterval = time.Hour
+)
+
+// Payload is the JSON body of a delivery
+type Payload struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Sign returns the X-Webhook-Signature header for body sent at timestamp:
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<unix seconds>.<body>">". Receivers
+// recompute it with their secret, and reject old timestamps to stop replays.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t + "."))
+	mac.Write(body)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatcher sends pending deliveries, retrying failures with exponential
+// backoff until MaxAttempts, after which they're marked dead
+type Dispatcher struct {
+	db           *bun.DB
+	client       *http.Client
+	pollInterval time.Duration
+	maxAttempts  int
+	baseBackoff  time.Duration
+	retention    time.Duration
+	lastPrune    time.Time
+	now          func() time.Time
+}
+
+func NewDispatcher(cfg *config.WebhookConfig, db *bun.DB) *Dispatcher {
+	return &Dispatcher{
+		db:           db,
+		client:       newClient(time.Duration(cfg.TimeoutSeconds) * time.Second),
+		pollInterval: time.Duration(cfg.PollIntervalSeconds) * time.Second,
+		maxAttempts:  cfg.MaxAttempts,
+		baseBackoff:  time.Duration(cfg.BackoffSeconds) * time.Second,
+		retention:    time.Duration(cfg.RetentionDays) * 24 * time.Hour,
+		now:          time.Now,
+	}
+}
+
+// Run sends due deliveries every poll interval until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	log.Printf("Webhook dispatcher started: polling every %s", d.pollInterval)
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := d.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Webhook delivery: %v", err)
+		}
+		d.prune(ctx)
+		select {
+		case <-ctx.Done():
+			log.Println("Webhook dispatcher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue sends every delivery that is due, batch by batch, and returns how many were attempted
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	attempted := 0
+	for {
+		deliveries, err := claimDueDeliveries(ctx, d.db, d.now(), claimLease, claimBatch)
+		if err != nil {
+			return attempted, fmt.Errorf("claiming deliveries: %w", err)
+		}
+		for _, delivery := range deliveries {
+			d.attempt(ctx, &delivery)
+			// Save the outcome even if ctx was cancelled mid-send
+			if err := recordAttempt(context.WithoutCancel(ctx), d.db, delivery); err != nil {
+				return attempted, fmt.Errorf("recording delivery %d: %w", delivery.ID, err)
+			}
+			attempted++
+		}
+		if len(deliveries) < claimBatch || ctx.Err() != nil {
+			return attempted, nil
+		}
+	}
+}
+
+// prune deletes events, and their delivered and dead deliveries, once they're
+// older than the retention, at most once every pruneInterval
+func (d *Dispatcher) prune(ctx context.Context) {
+	now := d.now()
+	if now.Sub(d.lastPrune) < pruneInterval {
+		return
+	}
+	d.lastPrune = now
+	pruned, err := pruneEvents(ctx, d.db, now.Add(-d.retention))
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Pruning webhook events: %v", err)
+		}
+		return
+	}
+	if pruned > 0 {
+		log.Printf("Pruned %d webhook events older than %s", pruned, d.retention)
+	}
+}
+
+// attempt sends delivery once and updates it with the outcome
+func (d *Dispatcher) attempt(ctx context.Context, delivery *Delivery) {
+	delivery.Attempts++
+	statusCode, err := d.send(ctx, delivery)
+	delivery.LastStatusCode = statusCode
+	if err == nil {
+		now := d.now()
+		delivery.Status = DeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+		return
+	}
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= d.maxAttempts {
+		delivery.Status = DeliveryDead
+		return
+	}
+	delivery.NextAttemptAt = d.now().Add(d.backoff(delivery.Attempts))
+}
+
+// backoff is how long to wait after the given number of failed attempts
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.baseBackoff
+	for i := 1; i < attempts && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, maxBackoff)
+}
+
+// send posts the delivery's event, returning the response status and an error
+// unless the endpoint answered with a 2xx
+func (d *Dispatcher) send(ctx context.Context, delivery *Delivery) (int, error) {
+	if delivery.Subscription == nil || delivery.Event == nil {
+		return 0, fmt.Errorf("delivery %d has no subscription or event", delivery.ID)
+	}
+	body, err := json.Marshal(Payload{
+		ID:        delivery.Event.ID,
+		Type:      delivery.Event.EventType,
+		CreatedAt: delivery.Event.CreatedAt,
+		Data:      delivery.Event.Payload,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.Event.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Subscription.Secret, d.now(), body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return resp.StatusCode, fmt.Errorf("endpoint returned %s: %s", resp.Status, bytes.TrimSpace(snippet))
+	}
+	return resp.StatusCode, nil
+}
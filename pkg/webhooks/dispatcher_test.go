@@ -0,0 +1,288 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mike/pkg/pagination"
+	"mike/pkg/routes/fixtures/models"
+	"mike/utils"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+func Test_Sign(t *testing.T) {
+	timestamp := time.Unix(1700000000, 0)
+	body := []byte(`{"id":1,"type":"fixture.created"}`)
+
+	signature := Sign("secret", timestamp, body)
+	assert.True(t, strings.HasPrefix(signature, "t=1700000000,v1="))
+	assert.Len(t, strings.TrimPrefix(signature, "t=1700000000,v1="), 64)
+	assert.Equal(t, signature, Sign("secret", timestamp, body))
+	assert.NotEqual(t, signature, Sign("other secret", timestamp, body))
+	assert.NotEqual(t, signature, Sign("secret", timestamp.Add(time.Second), body))
+	assert.NotEqual(t, signature, Sign("secret", timestamp, []byte(`{"id":2,"type":"fixture.created"}`)))
+}
+
+func Test_Backoff(t *testing.T) {
+	d := &Dispatcher{baseBackoff: 30 * time.Second}
+	assert.Equal(t, 30*time.Second, d.backoff(1))
+	assert.Equal(t, time.Minute, d.backoff(2))
+	assert.Equal(t, 2*time.Minute, d.backoff(3))
+	assert.Equal(t, maxBackoff, d.backoff(20))
+	assert.Equal(t, maxBackoff, d.backoff(1000))
+}
+
+func Test_CheckURL(t *testing.T) {
+	ctx := context.Background()
+	target, err := CheckURL(ctx, "https://93.184.215.14/hooks?team=1")
+	assert.NoError(t, err)
+	assert.Equal(t, "93.184.215.14", target.Hostname())
+
+	for _, rawURL := range []string{"ftp://93.184.215.14", "/hooks", "https://"} {
+		_, err := CheckURL(ctx, rawURL)
+		assert.EqualError(t, err, "url must be an absolute http or https URL", rawURL)
+	}
+	for _, rawURL := range []string{
+		"http://127.0.0.1:9000/hooks",
+		"http://localhost/hooks",
+		"http://10.1.2.3",
+		"http://192.168.0.10",
+		"http://172.16.0.1",
+		"http://100.64.0.1",
+		"http://169.254.169.254/latest/meta-data",
+		"http://0.0.0.0",
+		"http://[::1]/hooks",
+		"http://[fd00::1]",
+		"http://[fe80::1]",
+		"http://[::ffff:127.0.0.1]",
+	} {
+		_, err := CheckURL(ctx, rawURL)
+		assert.ErrorIs(t, err, errNotPublic, rawURL)
+	}
+}
+
+func Test_ClientRefusesPrivateAddresses(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	_, err := newClient(time.Second).Post(server.URL, "application/json", strings.NewReader("{}"))
+	assert.ErrorIs(t, err, errNotPublic)
+	assert.False(t, called)
+}
+
+// setupWebhookTestData creates a sport and two teams and returns their ids and a cleanup function
+func setupWebhookTestData(t *testing.T, db *bun.DB) (int, [2]int, func()) {
+	truncate := func() {
+		_, _ = db.NewRaw("TRUNCATE TABLE webhook_subscriptions CASCADE").Exec(context.Background())
+		_, _ = db.NewRaw("TRUNCATE TABLE fixture_events CASCADE").Exec(context.Background())
+		_, _ = db.NewRaw("TRUNCATE TABLE fixtures CASCADE").Exec(context.Background())
+		_, _ = db.NewRaw("TRUNCATE TABLE teams CASCADE").Exec(context.Background())
+		_, _ = db.NewRaw("TRUNCATE TABLE sports CASCADE").Exec(context.Background())
+	}
+	truncate()
+
+	sport := struct {
+		bun.BaseModel `bun:"sports"`
+		ID            int    `bun:"id,pk,autoincrement"`
+		Name          string `bun:"name"`
+		IsActive      bool   `bun:"is_active"`
+	}{Name: "Test Sport Webhooks", IsActive: true}
+	_, err := db.NewInsert().Model(&sport).Returning("id").Exec(context.Background())
+	assert.NoError(t, err)
+
+	var teamIDs [2]int
+	for i, name := range []string{"Home Webhooks", "Away Webhooks"} {
+		team := struct {
+			bun.BaseModel `bun:"teams"`
+			ID            int    `bun:"id,pk,autoincrement"`
+			Name          string `bun:"name"`
+			SportId       int    `bun:"sport_id"`
+			IsActive      bool   `bun:"is_active"`
+		}{Name: name, SportId: sport.ID, IsActive: true}
+		_, err := db.NewInsert().Model(&team).Returning("id").Exec(context.Background())
+		assert.NoError(t, err)
+		teamIDs[i] = team.ID
+	}
+	return sport.ID, teamIDs, truncate
+}
+
+func Test_DeliverDue(t *testing.T) {
+	db := utils.GetDatabase()
+	sportID, teamIDs, teardown := setupWebhookTestData(t, db)
+	defer teardown()
+
+	var mu sync.Mutex
+	var received []Payload
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		signature := r.Header.Get(HeaderSignature)
+		timestamp, _ := strconv.ParseInt(strings.TrimPrefix(strings.Split(signature, ",")[0], "t="), 10, 64)
+		if signature != Sign("receiver secret", time.Unix(timestamp, 0), body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var payload Payload
+		if err := json.Unmarshal(body, &payload); err != nil || payload.Type != r.Header.Get(HeaderEvent) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		received = append(received, payload)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	working := Subscription{URL: receiver.URL, Secret: "receiver secret", EventTypes: EventTypes, TeamID: teamIDs[0], IsActive: true}
+	assert.NoError(t, CreateSubscription(db, &working))
+	broken := Subscription{URL: failing.URL, Secret: "failing secret", EventTypes: []string{EventFixtureStatusChanged}, SportID: sportID, IsActive: true}
+	assert.NoError(t, CreateSubscription(db, &broken))
+	ctx := context.Background()
+	sport := struct {
+		bun.BaseModel `bun:"sports"`
+		ID            int    `bun:"id,pk,autoincrement"`
+		Name          string `bun:"name"`
+		IsActive      bool   `bun:"is_active"`
+	}{Name: "Other Sport Webhooks", IsActive: true}
+	_, err := db.NewInsert().Model(&sport).Returning("id").Exec(ctx)
+	assert.NoError(t, err)
+	otherSport := Subscription{URL: receiver.URL, Secret: "receiver secret", EventTypes: EventTypes, SportID: sport.ID, IsActive: true}
+	assert.NoError(t, CreateSubscription(db, &otherSport))
+
+	fixture := models.Fixture{
+		SportID: sportID, TeamID1: teamIDs[0], TeamID2: teamIDs[1],
+		DateTime: time.Date(2025, 8, 16, 14, 0, 0, 0, time.UTC), Status: models.StatusScheduled,
+	}
+	_, err = db.NewInsert().Model(&fixture).Returning("id").Exec(ctx)
+	assert.NoError(t, err)
+	_, err = db.NewUpdate().
+		Model((*models.Fixture)(nil)).
+		Set("status = ?", models.StatusPostponed).
+		Set("date_time = ?", time.Date(2025, 8, 17, 14, 0, 0, 0, time.UTC)).
+		Where("id = ?", fixture.ID).
+		Exec(ctx)
+	assert.NoError(t, err)
+
+	now := time.Now().UTC().Add(time.Minute)
+	d := &Dispatcher{
+		db:          db,
+		client:      receiver.Client(),
+		maxAttempts: 2,
+		baseBackoff: time.Minute,
+		now:         func() time.Time { return now },
+	}
+
+	// created, status_changed and rescheduled to the working endpoint, status_changed to the failing one
+	attempted, err := d.DeliverDue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, attempted)
+	types := []string{}
+	for _, payload := range received {
+		types = append(types, payload.Type)
+	}
+	assert.ElementsMatch(t, EventTypes, types)
+
+	var statusChanged map[string]json.RawMessage
+	for _, payload := range received {
+		if payload.Type == EventFixtureStatusChanged {
+			assert.NoError(t, json.Unmarshal(payload.Data, &statusChanged))
+		}
+	}
+	assert.JSONEq(t, `"scheduled"`, string(statusChanged["previous_status"]))
+
+	page, err := ListDeliveries(db, broken.ID, DeliveryPending, pagination.Params{Limit: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, page.Data, 1) {
+		assert.Equal(t, 1, page.Data[0].Attempts)
+		assert.Equal(t, http.StatusServiceUnavailable, page.Data[0].LastStatusCode)
+		assert.Contains(t, page.Data[0].LastError, "down for maintenance")
+	}
+
+	// Not due until the backoff has passed
+	attempted, err = d.DeliverDue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, attempted)
+
+	now = now.Add(2 * time.Minute)
+	attempted, err = d.DeliverDue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, attempted)
+
+	dead, err := ListDeliveries(db, broken.ID, DeliveryDead, pagination.Params{Limit: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, dead.Data, 1) {
+		assert.Equal(t, 2, dead.Data[0].Attempts)
+		assert.Equal(t, EventFixtureStatusChanged, dead.Data[0].Event.EventType)
+
+		retried, err := RetryDelivery(db, broken.ID, dead.Data[0].ID)
+		assert.NoError(t, err)
+		assert.Equal(t, DeliveryPending, retried.Status)
+		assert.Equal(t, 0, retried.Attempts)
+
+		_, err = RetryDelivery(db, broken.ID, dead.Data[0].ID)
+		assert.EqualError(t, err, "delivery not found")
+	}
+
+	// Events are kept past the retention while a delivery is pending
+	pruned, err := pruneEvents(ctx, db, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), pruned)
+	remaining, err := db.NewSelect().Model((*Event)(nil)).Count(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, remaining)
+}
+
+func Test_EventsOnlyRecordedForSubscribers(t *testing.T) {
+	db := utils.GetDatabase()
+	sportID, teamIDs, teardown := setupWebhookTestData(t, db)
+	defer teardown()
+	ctx := context.Background()
+
+	// A week apart, as the same teams can't play twice at once
+	kickoff := time.Date(2025, 8, 16, 14, 0, 0, 0, time.UTC)
+	insertFixture := func() {
+		fixture := models.Fixture{
+			SportID: sportID, TeamID1: teamIDs[0], TeamID2: teamIDs[1],
+			DateTime: kickoff, Status: models.StatusScheduled,
+		}
+		kickoff = kickoff.AddDate(0, 0, 7)
+		_, err := db.NewInsert().Model(&fixture).Exec(ctx)
+		assert.NoError(t, err)
+	}
+	countEvents := func() int {
+		count, err := db.NewSelect().Model((*Event)(nil)).Count(ctx)
+		assert.NoError(t, err)
+		return count
+	}
+
+	insertFixture()
+	assert.Equal(t, 0, countEvents())
+
+	inactive := Subscription{URL: "https://93.184.215.14", Secret: "secret", EventTypes: EventTypes, IsActive: false}
+	assert.NoError(t, CreateSubscription(db, &inactive))
+	otherEvent := Subscription{URL: "https://93.184.215.14", Secret: "secret", EventTypes: []string{EventFixtureRescheduled}, IsActive: true}
+	assert.NoError(t, CreateSubscription(db, &otherEvent))
+	insertFixture()
+	assert.Equal(t, 0, countEvents())
+
+	subscribed := Subscription{URL: "https://93.184.215.14", Secret: "secret", EventTypes: []string{EventFixtureCreated}, IsActive: true}
+	assert.NoError(t, CreateSubscription(db, &subscribed))
+	insertFixture()
+	assert.Equal(t, 1, countEvents())
+}
@@ -0,0 +1,231 @@
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"mike/pkg/pagination"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Event types a subscription can ask for
+const (
+	EventFixtureCreated       = "fixture.created"
+	EventFixtureStatusChanged = "fixture.status_changed"
+	EventFixtureRescheduled   = "fixture.rescheduled"
+)
+
+// EventTypes are all the event types, as recorded by the fixtures_record_events trigger
+var EventTypes = []string{EventFixtureCreated, EventFixtureStatusChanged, EventFixtureRescheduled}
+
+// Delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+// Subscription is an endpoint that fixture events matching its event types,
+// sport and team are delivered to
+type Subscription struct {
+	bun.BaseModel `bun:"webhook_subscriptions,alias:subscription"`
+	ID            int `bun:"id,pk,autoincrement" json:"id"`
+	// APIKeyID is the key that registered the subscription, the only one that can manage it
+	APIKeyID int    `bun:"api_key_id,nullzero" json:"-"`
+	URL      string `bun:"url" json:"url"`
+	// Secret signs every delivery; it is only shown when the subscription is created
+	Secret     string    `bun:"secret" json:"-"`
+	EventTypes []string  `bun:"event_types,array" json:"event_types"`
+	SportID    int       `bun:"sport_id,nullzero" json:"sport_id,omitempty"`
+	TeamID     int       `bun:"team_id,nullzero" json:"team_id,omitempty"`
+	IsActive   bool      `bun:"is_active" json:"is_active"`
+	CreatedAt  time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt  time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updated_at"`
+}
+
+// Event is a change to a fixture, recorded by the fixtures_record_events trigger
+type Event struct {
+	bun.BaseModel `bun:"fixture_events,alias:event"`
+	ID            int64           `bun:"id,pk,autoincrement" json:"id"`
+	EventType     string          `bun:"event_type" json:"type"`
+	FixtureID     int             `bun:"fixture_id" json:"fixture_id"`
+	Payload       json.RawMessage `bun:"payload,type:jsonb" json:"data"`
+	CreatedAt     time.Time       `bun:"created_at" json:"created_at"`
+}
+
+// Delivery is one event on its way to one subscription
+type Delivery struct {
+	bun.BaseModel  `bun:"webhook_deliveries,alias:delivery"`
+	ID             int64      `bun:"id,pk,autoincrement" json:"id"`
+	SubscriptionID int        `bun:"subscription_id" json:"subscription_id"`
+	EventID        int64      `bun:"event_id" json:"event_id"`
+	Status         string     `bun:"status" json:"status"`
+	Attempts       int        `bun:"attempts" json:"attempts"`
+	NextAttemptAt  time.Time  `bun:"next_attempt_at" json:"next_attempt_at"`
+	LastStatusCode int        `bun:"last_status_code,nullzero" json:"last_status_code,omitempty"`
+	LastError      string     `bun:"last_error,nullzero" json:"last_error,omitempty"`
+	CreatedAt      time.Time  `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
+	DeliveredAt    *time.Time `bun:"delivered_at" json:"delivered_at,omitempty"`
+
+	Subscription *Subscription `bun:"rel:belongs-to,join:subscription_id=id" json:"-"`
+	Event        *Event        `bun:"rel:belongs-to,join:event_id=id" json:"event,omitempty"`
+}
+
+func CreateSubscription(db *bun.DB, subscription *Subscription) error {
+	_, err := db.NewInsert().Model(subscription).Returning("*").Exec(context.Background())
+	return err
+}
+
+// GetSubscription returns the subscription with id, if apiKeyID registered it
+func GetSubscription(db *bun.DB, apiKeyID int, id int) (Subscription, error) {
+	var subscription Subscription
+	err := db.NewSelect().Model(&subscription).Where("id = ? AND api_key_id = ?", id, apiKeyID).Scan(context.Background())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Subscription{}, errors.New("subscription not found")
+		}
+		return Subscription{}, err
+	}
+	return subscription, nil
+}
+
+func subscriptionCursor(subscription Subscription) pagination.Cursor {
+	return pagination.Cursor{ID: subscription.ID}
+}
+
+// ListSubscriptions lists the subscriptions apiKeyID registered
+func ListSubscriptions(db *bun.DB, apiKeyID int, page pagination.Params) (pagination.Page[Subscription], error) {
+	var subscriptions []Subscription
+	q := db.NewSelect().Model(&subscriptions).Where("api_key_id = ?", apiKeyID)
+	if page.Cursor != nil {
+		q = q.Where("id > ?", page.Cursor.ID)
+	}
+	err := q.OrderExpr("id ASC").Limit(page.Limit + 1).Scan(context.Background())
+	if err != nil {
+		return pagination.Page[Subscription]{}, err
+	}
+	return pagination.NewPage(subscriptions, page.Limit, subscriptionCursor), nil
+}
+
+// DeleteSubscription removes a subscription apiKeyID registered along with its deliveries
+func DeleteSubscription(db *bun.DB, apiKeyID int, id int) error {
+	res, err := db.NewDelete().Model((*Subscription)(nil)).Where("id = ? AND api_key_id = ?", id, apiKeyID).Exec(context.Background())
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return errors.New("subscription not found")
+	}
+	return nil
+}
+
+func deliveryCursor(delivery Delivery) pagination.Cursor {
+	return pagination.Cursor{ID: int(delivery.ID)}
+}
+
+// ListDeliveries lists a subscription's deliveries, newest first, optionally
+// only those with status; the dead ones are its dead-letter list
+func ListDeliveries(db *bun.DB, subscriptionID int, status string, page pagination.Params) (pagination.Page[Delivery], error) {
+	var deliveries []Delivery
+	q := db.NewSelect().
+		Model(&deliveries).
+		Relation("Event").
+		Where("delivery.subscription_id = ?", subscriptionID)
+	if status != "" {
+		q = q.Where("delivery.status = ?", status)
+	}
+	if page.Cursor != nil {
+		q = q.Where("delivery.id < ?", page.Cursor.ID)
+	}
+	err := q.OrderExpr("delivery.id DESC").Limit(page.Limit + 1).Scan(context.Background())
+	if err != nil {
+		return pagination.Page[Delivery]{}, err
+	}
+	return pagination.NewPage(deliveries, page.Limit, deliveryCursor), nil
+}
+
+// RetryDelivery puts a dead delivery back in the queue with a fresh set of attempts
+func RetryDelivery(db *bun.DB, subscriptionID int, deliveryID int64) (Delivery, error) {
+	var delivery Delivery
+	err := db.NewUpdate().
+		Model(&delivery).
+		Set("status = ?", DeliveryPending).
+		Set("attempts = 0").
+		Set("next_attempt_at = current_timestamp").
+		Where("id = ? AND subscription_id = ? AND status = ?", deliveryID, subscriptionID, DeliveryDead).
+		Returning("*").
+		Scan(context.Background())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Delivery{}, errors.New("delivery not found")
+		}
+		return Delivery{}, err
+	}
+	return delivery, nil
+}
+
+// claimDueDeliveries takes up to limit pending deliveries that are due, pushing
+// their next attempt back by lease so other replicas leave them alone while
+// they're being sent
+func claimDueDeliveries(ctx context.Context, db *bun.DB, now time.Time, lease time.Duration, limit int) ([]Delivery, error) {
+	var claimed []Delivery
+	err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var ids []int64
+		err := tx.NewSelect().
+			Model((*Delivery)(nil)).
+			Column("id").
+			Where("status = ? AND next_attempt_at <= ?", DeliveryPending, now).
+			OrderExpr("next_attempt_at ASC, id ASC").
+			Limit(limit).
+			For("UPDATE SKIP LOCKED").
+			Scan(ctx, &ids)
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		_, err = tx.NewUpdate().
+			Model((*Delivery)(nil)).
+			Set("next_attempt_at = ?", now.Add(lease)).
+			Where("id IN (?)", bun.In(ids)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		return tx.NewSelect().
+			Model(&claimed).
+			Relation("Subscription").
+			Relation("Event").
+			Where("delivery.id IN (?)", bun.In(ids)).
+			OrderExpr("delivery.id ASC").
+			Scan(ctx)
+	})
+	return claimed, err
+}
+
+// recordAttempt saves the outcome of sending a delivery
+func recordAttempt(ctx context.Context, db *bun.DB, delivery Delivery) error {
+	_, err := db.NewUpdate().
+		Model(&delivery).
+		Column("status", "attempts", "next_attempt_at", "last_status_code", "last_error", "delivered_at").
+		WherePK().
+		Exec(ctx)
+	return err
+}
+
+// pruneEvents deletes events recorded before before, along with their
+// deliveries, unless a delivery is still pending
+func pruneEvents(ctx context.Context, db *bun.DB, before time.Time) (int64, error) {
+	res, err := db.NewDelete().
+		Model((*Event)(nil)).
+		Where("event.created_at < ?", before).
+		Where("NOT EXISTS (SELECT 1 FROM webhook_deliveries AS delivery WHERE delivery.event_id = event.id AND delivery.status = ?)", DeliveryPending).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}